@@ -0,0 +1,184 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures that opens a circuit
+	DefaultFailureThreshold = 5
+	// DefaultOpenDuration is the default time a circuit stays open before probing
+	DefaultOpenDuration = 30 * time.Second
+	// DefaultHalfOpenProbes is the default number of successful probes that close a circuit
+	DefaultHalfOpenProbes = 1
+)
+
+// CircuitBreakerConfig configures a circuit breaker around the parse endpoint
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before letting probes through
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of concurrent probe requests allowed while
+	// half-open, and the number of successes needed to close the circuit
+	HalfOpenProbes int
+}
+
+// CircuitState is the state of a circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// circuitBreaker tracks consecutive failures and short-circuits requests while open
+type circuitBreaker struct {
+	mu        sync.Mutex
+	config    CircuitBreakerConfig
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+	// generation changes on every state transition, so outcomes of requests
+	// allowed in an earlier state can be ignored
+	generation uint64
+	now        func() time.Time
+}
+
+// breakerTicket identifies an allowed request when its outcome is recorded
+type breakerTicket struct {
+	generation uint64
+	probe      bool
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.OpenDuration <= 0 {
+		config.OpenDuration = DefaultOpenDuration
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	return &circuitBreaker{config: config, state: CircuitClosed, now: time.Now}
+}
+
+// allow reports whether a request may proceed, and if not, how long until the next probe.
+// Every allowed request must be followed by a call to record with the returned ticket.
+func (b *circuitBreaker) allow() (breakerTicket, bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		elapsed := b.now().Sub(b.openedAt)
+		if elapsed < b.config.OpenDuration {
+			return breakerTicket{}, false, b.config.OpenDuration - elapsed
+		}
+		b.transition(CircuitHalfOpen)
+		b.probes, b.successes = 0, 0
+		fallthrough
+	case CircuitHalfOpen:
+		if b.probes >= b.config.HalfOpenProbes {
+			return breakerTicket{}, false, 0
+		}
+		b.probes++
+		return breakerTicket{generation: b.generation, probe: true}, true, 0
+	}
+	return breakerTicket{generation: b.generation}, true, 0
+}
+
+// record updates the breaker with the outcome of an allowed request.
+// Outcomes of requests allowed before the last state change are ignored: they
+// neither free a probe slot nor count as probes, and late failures do not
+// extend the open period.
+func (b *circuitBreaker) record(ticket breakerTicket, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ticket.generation != b.generation {
+		return
+	}
+	if ticket.probe {
+		b.probes--
+	}
+	failure, counted := classifyBreakerOutcome(err)
+	if !counted {
+		return
+	}
+
+	if failure {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+			b.transition(CircuitOpen)
+			b.openedAt = b.now()
+		}
+		return
+	}
+
+	b.failures = 0
+	if b.state == CircuitHalfOpen {
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.transition(CircuitClosed)
+		}
+	}
+}
+
+// transition moves the breaker to state and starts a new generation
+func (b *circuitBreaker) transition(state CircuitState) {
+	b.state = state
+	b.generation++
+}
+
+// snapshot returns the current state and consecutive failure count
+func (b *circuitBreaker) snapshot() (CircuitState, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.OpenDuration {
+		return CircuitHalfOpen, b.failures
+	}
+	return b.state, b.failures
+}
+
+// classifyBreakerOutcome reports whether err is an endpoint failure, and whether
+// the outcome should count at all. Server errors, gateway timeouts and transport
+// failures of the API exchange count as failures; other API responses such as
+// 422 show the endpoint is healthy; local errors, including failures to open or
+// read the document, and cancellations by the caller are ignored.
+func classifyBreakerOutcome(err error) (failure, counted bool) {
+	if err == nil {
+		return false, true
+	}
+	var srcErr *sourceReadError
+	if errors.Is(err, context.Canceled) || errors.As(err, &srcErr) {
+		return false, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsServerError() || apiErr.IsTimeout(), true
+	}
+	var valErr *ValidationErrors
+	if errors.As(err, &valErr) {
+		return false, true
+	}
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return transportErr.Op == opExecuteRequest || transportErr.Op == opReadResponseBody, true
+	}
+	return false, false
+}
+
+// isEndpointFailure reports whether err means the endpoint itself is unhealthy
+func isEndpointFailure(err error) bool {
+	failure, _ := classifyBreakerOutcome(err)
+	return failure
+}
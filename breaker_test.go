@@ -0,0 +1,160 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	b.now = func() time.Time { return now }
+
+	serverErr := &APIError{StatusCode: StatusInternalServerError}
+	validationErr := &APIError{StatusCode: StatusUnprocessableEntity}
+
+	for _, err := range []error{serverErr, validationErr, serverErr} {
+		ticket, ok, _ := b.allow()
+		if !ok {
+			t.Fatal("allow() = false while closed")
+		}
+		b.record(ticket, err)
+	}
+	if state, _ := b.snapshot(); state != CircuitClosed {
+		t.Fatalf("state = %s, want closed after a 422 reset the failure count", state)
+	}
+
+	ticket, _, _ := b.allow()
+	b.record(ticket, serverErr)
+	if _, ok, retryAfter := b.allow(); ok || retryAfter != time.Minute {
+		t.Fatalf("allow() = %v, %s, want rejected for 1m", ok, retryAfter)
+	}
+
+	now = now.Add(time.Minute)
+	probe, ok, _ := b.allow()
+	if !ok {
+		t.Fatal("allow() rejected the half-open probe")
+	}
+	if _, ok, _ := b.allow(); ok {
+		t.Fatal("allow() let a second concurrent probe through")
+	}
+	b.record(probe, nil)
+	if state, _ := b.snapshot(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed after a successful probe", state)
+	}
+}
+
+func TestCircuitBreaker_StaleOutcomes(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	b.now = func() time.Time { return now }
+	serverErr := &APIError{StatusCode: StatusInternalServerError}
+
+	// Two requests start while closed; the first one opens the circuit
+	slowFailure, _, _ := b.allow()
+	slowSuccess, _, _ := b.allow()
+	first, _, _ := b.allow()
+	b.record(first, serverErr)
+
+	// A late failure from the closed period must not extend the open period
+	now = now.Add(30 * time.Second)
+	b.record(slowFailure, serverErr)
+	now = now.Add(30 * time.Second)
+
+	probe, ok, _ := b.allow()
+	if !ok {
+		t.Fatal("allow() rejected the probe after the open period")
+	}
+	// A late success from the closed period is not a probe: it must neither
+	// close the circuit nor free the probe slot
+	b.record(slowSuccess, nil)
+	if state, _ := b.snapshot(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open until the real probe finishes", state)
+	}
+	if _, ok, _ := b.allow(); ok {
+		t.Fatal("allow() let a second probe through after a stale outcome")
+	}
+
+	b.record(probe, nil)
+	if state, _ := b.snapshot(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed after the probe succeeded", state)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key",
+		WithBaseURL(server.URL),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour}),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Parse(context.Background()).WithFileData([]byte("data"), "doc.pdf").Do()
+		if err == nil {
+			t.Fatal("Do() succeeded against a failing server")
+		}
+		if i == 2 && !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Do() error = %v, want ErrCircuitOpen", err)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server called %d times, want 2", calls.Load())
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Errorf("CircuitState() = %s, want open", client.CircuitState())
+	}
+}
+
+func TestWithCircuitBreaker_IgnoresSourceErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key",
+		WithBaseURL(server.URL),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour}),
+	)
+
+	// Documents that cannot be downloaded or read say nothing about the API
+	sources := []*ParseRequestBuilder{
+		client.Parse(context.Background()).WithSourceURI("http://127.0.0.1:1/doc.pdf"),
+		client.Parse(context.Background()).WithSourceURI("http://127.0.0.1:1/doc.pdf"),
+		client.Parse(context.Background()).WithSource(ReaderSource(
+			io.MultiReader(strings.NewReader("%PDF"), iotest.ErrReader(errors.New("disk failure"))), "doc.pdf")),
+		client.Parse(context.Background()).WithSource(ReaderSource(
+			io.MultiReader(strings.NewReader("%PDF"), iotest.ErrReader(errors.New("disk failure"))), "doc.pdf")),
+	}
+	for _, builder := range sources {
+		if _, err := builder.Do(); err == nil {
+			t.Fatal("Do() succeeded with an unreadable document")
+		}
+	}
+	if client.CircuitState() != CircuitClosed {
+		t.Fatalf("CircuitState() = %s, want closed", client.CircuitState())
+	}
+
+	if _, err := client.Parse(context.Background()).WithFileData([]byte("%PDF"), "doc.pdf").Do(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if calls.Load() == 0 {
+		t.Error("API never called")
+	}
+}
@@ -2,6 +2,7 @@ package landingai
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -13,10 +14,20 @@ const (
 
 // Client is the main client for interacting with the Landing AI API
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	region     Region
+	keyHint     string
+	credentials CredentialsProvider
+	baseURL     string
+	httpClient  *http.Client
+	region      Region
+	logger      *slog.Logger
+	capCheck    CapabilityCheck
+	breaker     *circuitBreaker
+	fetchers    map[string]Fetcher
+	userAgent   string
+	header      http.Header
+
+	autoIdempotency bool
+	inflight        flightGroup
 }
 
 // ClientOption is a function that configures a Client
@@ -25,8 +36,10 @@ type ClientOption func(*Client)
 // NewClient creates a new Landing AI client with the given API key
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	client := &Client{
-		apiKey: apiKey,
-		region: RegionUS,
+		keyHint:     redactKey(apiKey),
+		credentials: StaticCredentials(apiKey),
+		region:      RegionUS,
+		logger:      slog.Default(),
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
@@ -74,6 +87,35 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithLogger sets the logger used for warnings such as model capability mismatches.
+// A nil logger discards them.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
+
+// WithCapabilityCheck sets how requests react when the chosen model lacks a required feature
+func WithCapabilityCheck(check CapabilityCheck) ClientOption {
+	return func(c *Client) {
+		c.capCheck = check
+	}
+}
+
+// WithCircuitBreaker wraps the parse endpoint in a circuit breaker. After
+// FailureThreshold consecutive server errors, gateway timeouts or transport
+// failures, requests fail fast with ErrCircuitOpen for OpenDuration, after which
+// HalfOpenProbes probe requests decide whether the circuit closes again.
+// Client errors such as 422 never trip the breaker.
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(config)
+	}
+}
+
 // Parse initiates a document parsing request
 func (c *Client) Parse(ctx context.Context) *ParseRequestBuilder {
 	return &ParseRequestBuilder{
@@ -82,9 +124,25 @@ func (c *Client) Parse(ctx context.Context) *ParseRequestBuilder {
 	}
 }
 
-// APIKey returns the API key
+// APIKey returns a redacted form of the API key passed to NewClient, showing
+// only its last 4 characters, e.g. "****abcd". It is empty for clients created
+// with NewClientFromEnv.
+//
+// Deprecated: the client no longer exposes its API key. Keys are held by the
+// CredentialsProvider and fetched per request.
 func (c *Client) APIKey() string {
-	return c.apiKey
+	return c.keyHint
+}
+
+// redactKey masks all but the last 4 characters of a key
+func redactKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
 }
 
 // BaseURL returns the base URL
@@ -101,3 +159,17 @@ func (c *Client) HTTPClient() *http.Client {
 func (c *Client) Region() Region {
 	return c.region
 }
+
+// CircuitState returns the state of the circuit breaker, or CircuitClosed if none is configured
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	state, _ := c.breaker.snapshot()
+	return state
+}
+
+// Logger returns the logger
+func (c *Client) Logger() *slog.Logger {
+	return c.logger
+}
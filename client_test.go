@@ -1,6 +1,8 @@
 package landingai
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -40,8 +42,8 @@ func TestNewClient(t *testing.T) {
 				t.Error("NewClient() returned nil")
 				return
 			}
-			if client.APIKey() != tt.apiKey {
-				t.Errorf("APIKey() = %v, want %v", client.APIKey(), tt.apiKey)
+			if got := client.APIKey(); got != "****-key" {
+				t.Errorf("APIKey() = %v, want redacted key", got)
 			}
 			if client.BaseURL() == "" {
 				t.Error("BaseURL() returned empty string")
@@ -50,6 +52,23 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClient_APIKeyRedacted(t *testing.T) {
+	const secret = "sk-live-0123456789abcdef"
+	client := NewClient(secret)
+
+	if got := client.APIKey(); got != "****cdef" || strings.Contains(got, "0123456789") {
+		t.Errorf("APIKey() = %q, want only the last 4 characters", got)
+	}
+	for _, format := range []string{"%v", "%s", "%#v"} {
+		if got := fmt.Sprintf(format, StaticCredentials(secret)); strings.Contains(got, secret) {
+			t.Errorf("Sprintf(%q, StaticCredentials) = %s, want redacted", format, got)
+		}
+	}
+	if got := NewClient("short").APIKey(); got != "****" {
+		t.Errorf("APIKey() for a short key = %q, want fully masked", got)
+	}
+}
+
 func TestRegion_BaseURL(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/youssefsiam38/landingai/eval"
+)
+
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: landingai eval [flags] <truth-dir> [prediction-dir]")
+		fmt.Fprintf(fs.Output(), "\nScores <name>.json predictions against <name>%s ground truth files.\n", eval.TruthSuffix)
+		fmt.Fprintln(fs.Output(), "Predictions are read from truth-dir unless prediction-dir is given.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	iou := fs.Float64("iou", eval.DefaultIoUThreshold, "minimum box IoU for a predicted chunk to match a labeled one")
+	asJSON := fs.Bool("json", false, "print the full summary as JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	truthDir, predDir := fs.Arg(0), fs.Arg(0)
+	if fs.NArg() == 2 {
+		predDir = fs.Arg(1)
+	}
+
+	cases, err := eval.LoadCorpus(truthDir, predDir)
+	if err != nil {
+		return err
+	}
+	if len(cases) == 0 {
+		return fmt.Errorf("no *%s files found in %s", eval.TruthSuffix, truthDir)
+	}
+
+	summary := eval.Run(cases, eval.Options{IoUThreshold: *iou})
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	return summary.WriteTable(os.Stdout)
+}
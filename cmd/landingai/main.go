@@ -0,0 +1,59 @@
+// Command landingai is a command-line interface to the Landing AI Go SDK.
+//
+// Usage:
+//
+//	landingai <command> [flags] [args]
+//
+// Run "landingai help" for the list of commands.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command is a CLI subcommand
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"eval":  {summary: "score parse results against ground truth", run: runEval},
+	"parse": {summary: "parse documents with a progress bar", run: runParse},
+	"serve": {summary: "run an HTTP gateway in front of the API", run: runServe},
+	"watch": {summary: "parse documents dropped into a directory", run: runWatch},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage()
+		return
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "landingai: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "landingai %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: landingai <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun \"landingai <command> -h\" for command flags.")
+}
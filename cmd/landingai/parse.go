@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func runParse(args []string) error {
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: landingai parse [flags] <file|url>...")
+		fmt.Fprintln(fs.Output(), "\nParses documents and writes their markdown (or JSON with -json).")
+		fmt.Fprintln(fs.Output(), "A single document is written to stdout unless -out is set.")
+		fmt.Fprintf(fs.Output(), "The API key and region are read from %s and %s.\n", landingai.EnvAPIKey, landingai.EnvRegion)
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	out := fs.String("out", "", "output directory, required for several documents")
+	config := fs.String("config", "", "options file (.json, .yaml or .yml); flags override it")
+	model := fs.String("model", "", "model to parse with, e.g. dpt-2-latest")
+	split := fs.String("split", "", `split documents, e.g. "page"`)
+	asJSON := fs.Bool("json", false, "write the full JSON response instead of markdown")
+	concurrency := fs.Int("concurrency", 4, "documents parsed in parallel")
+	quiet := fs.Bool("quiet", false, "do not show progress")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	docs := fs.Args()
+	if len(docs) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if len(docs) > 1 && *out == "" {
+		return fmt.Errorf("-out is required when parsing several documents")
+	}
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+
+	var opts landingai.ParseOptions
+	if *config != "" {
+		var err error
+		if opts, err = landingai.LoadParseOptions(*config); err != nil {
+			return err
+		}
+	}
+	if *model != "" {
+		m := landingai.Model(*model)
+		opts = opts.Merge(landingai.ParseOptions{Model: &m})
+	}
+	if *split != "" {
+		s := landingai.SplitType(*split)
+		opts = opts.Merge(landingai.ParseOptions{Split: &s})
+	}
+
+	client, err := landingai.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bar := newProgressBar(os.Stderr, *quiet)
+	group := landingai.NewProgressGroup(len(docs), func(bp landingai.BatchProgress) {
+		if len(docs) == 1 {
+			bar.single(docs[0], bp.Items[0])
+		} else {
+			bar.batch(bp)
+		}
+	})
+
+	errs := make([]error, len(docs))
+	sem := make(chan struct{}, *concurrency)
+	var wg sync.WaitGroup
+	for i, doc := range docs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, doc string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			builder := client.Parse(ctx).Apply(opts).WithProgress(group.Track(i))
+			if strings.HasPrefix(doc, "http://") || strings.HasPrefix(doc, "https://") {
+				builder.WithURL(doc)
+			} else {
+				builder.WithFile(doc)
+			}
+
+			resp, err := builder.Do()
+			if err == nil {
+				err = writeParseOutput(*out, doc, resp, *asJSON)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", doc, flagError(err))
+			}
+			group.Finish(i, err)
+		}(i, doc)
+	}
+	wg.Wait()
+	bar.finish()
+	return errors.Join(errs...)
+}
+
+// parseFlags maps API request fields to the parse command flags that set them
+var parseFlags = map[string]string{
+	"document":     "<file>",
+	"document_url": "<url>",
+	"model":        "-model",
+	"split":        "-split",
+}
+
+// flagError rewrites validation errors in terms of command-line flags
+func flagError(err error) error {
+	var valErr *landingai.ValidationErrors
+	if !errors.As(err, &valErr) {
+		return err
+	}
+	msgs := make([]string, len(valErr.Detail))
+	for i, d := range valErr.Detail {
+		if flag, ok := parseFlags[d.Path().Field()]; ok {
+			msgs[i] = flag + ": " + d.Message
+		} else {
+			msgs[i] = d.Error()
+		}
+	}
+	return fmt.Errorf("invalid request: %s", strings.Join(msgs, "; "))
+}
+
+// writeParseOutput writes a result to stdout, or to the output directory
+func writeParseOutput(dir, doc string, resp *landingai.ParseResponse, asJSON bool) error {
+	data := []byte(resp.Markdown)
+	ext := ".md"
+	if asJSON {
+		var err error
+		if data, err = json.MarshalIndent(resp, "", "  "); err != nil {
+			return err
+		}
+		ext = ".json"
+	}
+	if dir == "" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, outputName(doc)+ext), data, 0o644)
+}
+
+// outputName names a document's results after its file name, extension
+// included, so a.pdf and a.png do not overwrite each other. URLs use the last
+// path segment without the query string, or the host if the path is empty.
+func outputName(doc string) string {
+	if u, err := url.Parse(doc); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if name := path.Base(u.Path); name != "." && name != "/" {
+			return name
+		}
+		return u.Hostname()
+	}
+	return filepath.Base(doc)
+}
+
+// barWidth is the number of cells in the progress bar
+const barWidth = 30
+
+// progressBar renders progress on a single terminal line
+type progressBar struct {
+	w     io.Writer
+	quiet bool
+	mu    sync.Mutex
+	last  time.Time
+	drawn bool
+}
+
+func newProgressBar(w io.Writer, quiet bool) *progressBar {
+	return &progressBar{w: w, quiet: quiet}
+}
+
+// single renders the progress of one document
+func (b *progressBar) single(name string, p landingai.Progress) {
+	var detail string
+	switch p.Phase {
+	case landingai.ProgressUploading:
+		detail = "uploading " + formatBytes(p.BytesSent, p.TotalBytes)
+	case landingai.ProgressProcessing:
+		detail = "processing"
+	case landingai.ProgressDownloading:
+		detail = "downloading " + formatBytes(p.BytesReceived, p.ResponseBytes)
+	case landingai.ProgressDone:
+		detail = "done"
+	}
+	b.draw(filepath.Base(name), p.Fraction(), fmt.Sprintf("%s %s", detail, p.Elapsed.Truncate(time.Second)), p.Phase == landingai.ProgressDone)
+}
+
+// batch renders the aggregate progress of several documents
+func (b *progressBar) batch(bp landingai.BatchProgress) {
+	detail := fmt.Sprintf("%d/%d done", bp.Completed+bp.Failed, bp.Total)
+	if bp.Failed > 0 {
+		detail += fmt.Sprintf(", %d failed", bp.Failed)
+	}
+	if bp.TotalBytes > 0 {
+		detail += ", uploaded " + formatBytes(bp.BytesSent, bp.TotalBytes)
+	}
+	b.draw("parsing", bp.Fraction(), detail, bp.Completed+bp.Failed == bp.Total)
+}
+
+// draw redraws the line, at most every 100ms unless force is set
+func (b *progressBar) draw(label string, fraction float64, detail string, force bool) {
+	if b.quiet {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !force && time.Since(b.last) < 100*time.Millisecond {
+		return
+	}
+	b.last = time.Now()
+
+	cells := strings.Repeat("-", barWidth)
+	percent := "   "
+	if fraction >= 0 {
+		filled := int(fraction * barWidth)
+		cells = strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+		percent = fmt.Sprintf("%3.0f%%", fraction*100)
+	}
+	// Pad to clear leftovers of a longer previous line
+	fmt.Fprintf(b.w, "\r%s [%s] %s %-40s", label, cells, percent, detail)
+	b.drawn = true
+}
+
+// finish ends the progress line
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.drawn {
+		fmt.Fprintln(b.w)
+	}
+}
+
+// formatBytes formats n out of total bytes, total being -1 if unknown
+func formatBytes(n, total int64) string {
+	if total < 0 {
+		return humanBytes(n)
+	}
+	return humanBytes(n) + "/" + humanBytes(total)
+}
+
+func humanBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
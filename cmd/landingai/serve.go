@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+	"github.com/youssefsiam38/landingai/server"
+)
+
+// envServeTokens holds tenant tokens as comma-separated tenant:token pairs
+const envServeTokens = "LANDINGAI_SERVE_TOKENS"
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: landingai serve [flags]")
+		fmt.Fprintln(fs.Output(), "\nRuns an HTTP gateway that parses documents on behalf of internal services.")
+		fmt.Fprintf(fs.Output(), "The API key and region are read from %s and %s.\n", landingai.EnvAPIKey, landingai.EnvRegion)
+		fmt.Fprintf(fs.Output(), "Tenant tokens are read from -tokens or %s (tenant:token,...).\n", envServeTokens)
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", ":8080", "listen address")
+	tokensFile := fs.String("tokens", "", `file with one "tenant token" pair per line`)
+	workers := fs.Int("workers", server.DefaultWorkers, "concurrent upstream requests")
+	queueSize := fs.Int("queue", server.DefaultQueueSize, "jobs that may wait for a worker")
+	maxUpload := fs.Int64("max-upload", server.DefaultMaxUploadBytes, "maximum document size in bytes")
+	spoolDir := fs.String("spool-dir", "", "directory for queued uploads (default the system temp directory)")
+	retention := fs.Duration("retention", 24*time.Hour, "how long finished jobs are kept")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tokens, err := loadTokens(*tokensFile)
+	if err != nil {
+		return err
+	}
+	client, err := landingai.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	srv, err := server.New(server.Config{
+		Client:         client,
+		Tokens:         tokens,
+		Workers:        *workers,
+		QueueSize:      *queueSize,
+		MaxUploadBytes: *maxUpload,
+		SpoolDir:       *spoolDir,
+		Store:          server.NewMemoryStore(*retention),
+		Logger:         slog.Default(),
+	})
+	if err != nil {
+		return err
+	}
+	// Runs after the HTTP server has drained, failing jobs that are still queued
+	defer srv.Close()
+
+	httpServer := &http.Server{Addr: *addr, Handler: srv}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("landingai: shutdown timed out, cancelling in-flight requests", "error", err)
+		}
+	}()
+
+	slog.Info("landingai: serving", "addr", *addr, "tenants", len(tokens), "workers", *workers)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	// ListenAndServe returns as soon as Shutdown starts; wait for in-flight requests
+	<-drained
+	return nil
+}
+
+// loadTokens reads tenant tokens from a file, falling back to the environment
+func loadTokens(path string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	add := func(tenant, token string) error {
+		if tenant == "" || token == "" {
+			return fmt.Errorf("invalid tenant token entry for tenant %q", tenant)
+		}
+		if _, dup := tokens[token]; dup {
+			return fmt.Errorf("token for tenant %q is already in use", tenant)
+		}
+		tokens[token] = tenant
+		return nil
+	}
+
+	if path == "" {
+		for _, pair := range splitList(os.Getenv(envServeTokens)) {
+			tenant, token, _ := strings.Cut(pair, ":")
+			if err := add(strings.TrimSpace(tenant), strings.TrimSpace(token)); err != nil {
+				return nil, err
+			}
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("no tenant tokens: set -tokens or %s", envServeTokens)
+		}
+		return tokens, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tokens file: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line in tokens file: %q", line)
+		}
+		if err := add(fields[0], fields[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	return tokens, nil
+}
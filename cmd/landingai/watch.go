@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/youssefsiam38/landingai"
+	"github.com/youssefsiam38/landingai/watch"
+)
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: landingai watch [flags] <dir>")
+		fmt.Fprintln(fs.Output(), "\nParses documents dropped into dir and writes .json/.md results.")
+		fmt.Fprintf(fs.Output(), "The API key and region are read from %s and %s.\n", landingai.EnvAPIKey, landingai.EnvRegion)
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	out := fs.String("out", "", "output directory (default: the watched directory)")
+	model := fs.String("model", "", "model to parse with, e.g. dpt-2-latest")
+	split := fs.String("split", "", `split documents, e.g. "page"`)
+	formats := fs.String("formats", "json,md", "comma-separated output formats (json, md)")
+	patterns := fs.String("patterns", "", "comma-separated file patterns (default: supported document types)")
+	interval := fs.Duration("interval", watch.DefaultInterval, "polling interval")
+	debounce := fs.Duration("debounce", watch.DefaultDebounce, "time a file must stay unchanged before parsing")
+	concurrency := fs.Int("concurrency", 1, "files parsed in parallel")
+	stateFile := fs.String("state", "", "state file tracking processed files (default: <out>/"+watch.DefaultStateFile+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client, err := landingai.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	opts := watch.Options{
+		Dir:         fs.Arg(0),
+		OutputDir:   *out,
+		Model:       landingai.Model(*model),
+		Interval:    *interval,
+		Debounce:    *debounce,
+		Concurrency: *concurrency,
+		StateFile:   *stateFile,
+		Patterns:    splitList(*patterns),
+	}
+	if *split != "" {
+		s := landingai.SplitType(*split)
+		opts.Split = &s
+	}
+	for _, f := range splitList(*formats) {
+		opts.Formats = append(opts.Formats, watch.Format(f))
+	}
+
+	w, err := watch.New(client, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := w.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,190 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv and the environment providers
+const (
+	EnvAPIKey     = "LANDINGAI_API_KEY"
+	EnvAPIKeyFile = "LANDINGAI_API_KEY_FILE"
+	EnvRegion     = "LANDINGAI_REGION"
+)
+
+// ErrNoCredentials is returned when a credentials provider has no API key
+var ErrNoCredentials = errors.New("landingai: no API key available")
+
+// CredentialsProvider supplies the API key for each request.
+// Providers are called lazily, once per request, and should cache as needed.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialsInvalidator is implemented by providers that cache keys.
+// The client calls Invalidate when the API rejects a key with 401, then fetches
+// the key again and retries the request once if it changed.
+type CredentialsInvalidator interface {
+	Invalidate()
+}
+
+// StaticCredentials is a provider that always returns the same key
+type StaticCredentials string
+
+// APIKey implements CredentialsProvider
+func (s StaticCredentials) APIKey(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", ErrNoCredentials
+	}
+	return string(s), nil
+}
+
+// String redacts the key so it is not exposed when formatted or logged
+func (s StaticCredentials) String() string {
+	return redactKey(string(s))
+}
+
+// GoString redacts the key for %#v
+func (s StaticCredentials) GoString() string {
+	return "landingai.StaticCredentials(" + strconv.Quote(redactKey(string(s))) + ")"
+}
+
+// EnvCredentials reads the API key from an environment variable on every request
+type EnvCredentials struct {
+	// Name is the variable to read (default EnvAPIKey)
+	Name string
+}
+
+// APIKey implements CredentialsProvider
+func (e EnvCredentials) APIKey(ctx context.Context) (string, error) {
+	name := e.Name
+	if name == "" {
+		name = EnvAPIKey
+	}
+	key := strings.TrimSpace(os.Getenv(name))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is not set", ErrNoCredentials, name)
+	}
+	return key, nil
+}
+
+// FileCredentials reads the API key from a file, such as a mounted secret.
+// The key is cached and re-read when the file's modification time changes or
+// after Invalidate.
+type FileCredentials struct {
+	Path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+}
+
+// NewFileCredentials creates a provider reading the key from path
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{Path: path}
+}
+
+// APIKey implements CredentialsProvider
+func (f *FileCredentials) APIKey(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat API key file: %w", err)
+	}
+	if f.key != "" && info.ModTime().Equal(f.modTime) {
+		return f.key, nil
+	}
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read API key file: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrNoCredentials, f.Path)
+	}
+	f.key, f.modTime = key, info.ModTime()
+	return key, nil
+}
+
+// Invalidate implements CredentialsInvalidator
+func (f *FileCredentials) Invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.key = ""
+}
+
+// ChainCredentials returns the key from the first provider that has one
+type ChainCredentials []CredentialsProvider
+
+// APIKey implements CredentialsProvider
+func (c ChainCredentials) APIKey(ctx context.Context) (string, error) {
+	var errs []error
+	for _, p := range c {
+		key, err := p.APIKey(ctx)
+		if err == nil && key != "" {
+			return key, nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return "", ErrNoCredentials
+	}
+	return "", errors.Join(errs...)
+}
+
+// Invalidate implements CredentialsInvalidator by invalidating every provider in the chain
+func (c ChainCredentials) Invalidate() {
+	for _, p := range c {
+		if inv, ok := p.(CredentialsInvalidator); ok {
+			inv.Invalidate()
+		}
+	}
+}
+
+// WithCredentials sets the provider that supplies the API key for each request,
+// replacing the key passed to NewClient
+func WithCredentials(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// NewClientFromEnv creates a client configured from the environment.
+// The API key is read lazily from LANDINGAI_API_KEY, falling back to the file
+// named by LANDINGAI_API_KEY_FILE. The region is read from LANDINGAI_REGION
+// ("us" or "eu", default "us"). Options override the environment.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	var chain ChainCredentials
+	if os.Getenv(EnvAPIKey) != "" {
+		chain = append(chain, EnvCredentials{})
+	}
+	if path := os.Getenv(EnvAPIKeyFile); path != "" {
+		chain = append(chain, NewFileCredentials(path))
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%w: set %s or %s", ErrNoCredentials, EnvAPIKey, EnvAPIKeyFile)
+	}
+
+	envOpts := []ClientOption{WithCredentials(chain)}
+	if region := strings.ToLower(strings.TrimSpace(os.Getenv(EnvRegion))); region != "" {
+		switch Region(region) {
+		case RegionUS, RegionEU:
+			envOpts = append(envOpts, WithRegion(Region(region)))
+		default:
+			return nil, fmt.Errorf("invalid %s %q: must be %q or %q", EnvRegion, region, RegionUS, RegionEU)
+		}
+	}
+
+	return NewClient("", append(envOpts, opts...)...), nil
+}
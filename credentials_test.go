@@ -0,0 +1,108 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvAPIKeyFile, "")
+	t.Setenv(EnvRegion, "EU")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv() error = %v", err)
+	}
+	if client.Region() != RegionEU || client.BaseURL() != RegionEU.BaseURL() {
+		t.Errorf("Region() = %s, BaseURL() = %s, want EU", client.Region(), client.BaseURL())
+	}
+	if client.APIKey() != "" {
+		t.Error("APIKey() exposed the environment key")
+	}
+
+	t.Setenv(EnvRegion, "mars")
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Error("NewClientFromEnv() accepted an invalid region")
+	}
+
+	t.Setenv(EnvAPIKey, "")
+	if _, err := NewClientFromEnv(); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("NewClientFromEnv() error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	t.Setenv("TEST_LANDINGAI_KEY", "")
+	chain := ChainCredentials{EnvCredentials{Name: "TEST_LANDINGAI_KEY"}, StaticCredentials("fallback")}
+
+	key, err := chain.APIKey(context.Background())
+	if err != nil || key != "fallback" {
+		t.Errorf("APIKey() = %q, %v, want fallback", key, err)
+	}
+}
+
+func TestFileCredentials_RotationRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("old-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	creds := NewFileCredentials(path)
+
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		seen = append(seen, auth)
+		if auth != "Bearer new-key" {
+			w.WriteHeader(StatusUnauthorized)
+			// Rotate the key as a secret manager would
+			os.WriteFile(path, []byte("new-key"), 0o600)
+			os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+			return
+		}
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", WithCredentials(creds), WithBaseURL(server.URL))
+	resp, err := client.Parse(context.Background()).WithFileData([]byte("data"), "doc.pdf").Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.Markdown != "ok" || len(seen) != 2 || seen[0] != "Bearer old-key" {
+		t.Errorf("Do() = %+v after requests with %v", resp, seen)
+	}
+}
+
+func TestFileCredentials_RotationWithSingleUseSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("old-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(StatusUnauthorized)
+		os.WriteFile(path, []byte("new-key"), 0o600)
+		os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+	}))
+	defer server.Close()
+
+	// The reader cannot be replayed, so the 401 is returned rather than a source error
+	client := NewClient("", WithCredentials(NewFileCredentials(path)), WithBaseURL(server.URL))
+	_, err := client.Parse(context.Background()).WithSource(ReaderSource(strings.NewReader("data"), "doc.pdf")).Do()
+	if !errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrSourceConsumed) {
+		t.Errorf("Do() error = %v, want ErrUnauthorized", err)
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
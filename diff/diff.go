@@ -0,0 +1,337 @@
+// Package diff compares two ParseResponses for the same document, e.g. to find
+// regressions when switching between model snapshots.
+package diff
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/youssefsiam38/landingai"
+	"github.com/youssefsiam38/landingai/internal/markdown"
+	"github.com/youssefsiam38/landingai/internal/strdist"
+)
+
+const (
+	// DefaultMinIoU is the minimum box IoU for two chunks to be aligned on geometry alone
+	DefaultMinIoU = 0.5
+	// DefaultMinSimilarity is the minimum text similarity for two chunks to be aligned on content alone
+	DefaultMinSimilarity = 0.8
+	// DefaultWindow is how many positions apart two chunks on a page may be to be compared fuzzily
+	DefaultWindow = 10
+)
+
+// Options configures Compare
+type Options struct {
+	// MinIoU is the box IoU at which chunks on the same page are aligned (default DefaultMinIoU)
+	MinIoU float64
+	// MinSimilarity is the text similarity at which chunks on the same page are
+	// aligned even if their boxes moved (default DefaultMinSimilarity)
+	MinSimilarity float64
+	// Window limits fuzzy matching to chunks whose positions within their page
+	// differ by at most Window (default DefaultWindow). Chunks with identical
+	// text are aligned regardless of position.
+	Window int
+}
+
+// ChangeKind describes how a chunk changed between two responses
+type ChangeKind string
+
+const (
+	ChangeUnchanged ChangeKind = "unchanged"
+	ChangeAdded     ChangeKind = "added"
+	ChangeRemoved   ChangeKind = "removed"
+	ChangeModified  ChangeKind = "modified"
+)
+
+// ChunkChange describes one aligned, added or removed chunk.
+// Old is nil for added chunks and New is nil for removed chunks.
+type ChunkChange struct {
+	Kind        ChangeKind            `json:"kind"`
+	Old         *landingai.ParseChunk `json:"old,omitempty"`
+	New         *landingai.ParseChunk `json:"new,omitempty"`
+	IoU         float64               `json:"iou"`
+	Similarity  float64               `json:"similarity"`
+	TypeChanged bool                  `json:"type_changed"`
+	TextDiff    []LineEdit            `json:"text_diff,omitempty"`
+	CellChanges []CellChange          `json:"cell_changes,omitempty"`
+}
+
+// Page returns the page of the chunk
+func (c ChunkChange) Page() int {
+	if c.New != nil {
+		return c.New.Grounding.Page
+	}
+	return c.Old.Grounding.Page
+}
+
+// CellChange describes a table cell whose text differs.
+// Cells missing on one side have an empty Old or New value.
+type CellChange struct {
+	Table int    `json:"table"`
+	Row   int    `json:"row"`
+	Col   int    `json:"col"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Report is the result of comparing two responses
+type Report struct {
+	OldModel  string        `json:"old_model,omitempty"`
+	NewModel  string        `json:"new_model,omitempty"`
+	Changes   []ChunkChange `json:"changes"`
+	Unchanged int           `json:"unchanged"`
+	Added     int           `json:"added"`
+	Removed   int           `json:"removed"`
+	Modified  int           `json:"modified"`
+}
+
+// HasChanges returns true if any chunk was added, removed or modified
+func (r *Report) HasChanges() bool {
+	return r.Added+r.Removed+r.Modified > 0
+}
+
+// Compare aligns the chunks of two responses by box IoU and text similarity
+// and reports added, removed and modified chunks in page order. Aligned chunks
+// are modified if their type or markdown differs.
+// Chunks with identical text are aligned first; the rest are compared only
+// within Options.Window positions of each other on the same page.
+func Compare(oldResp, newResp *landingai.ParseResponse, opts Options) *Report {
+	if opts.MinIoU <= 0 {
+		opts.MinIoU = DefaultMinIoU
+	}
+	if opts.MinSimilarity <= 0 {
+		opts.MinSimilarity = DefaultMinSimilarity
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+
+	report := &Report{
+		OldModel: modelOf(oldResp),
+		NewModel: modelOf(newResp),
+	}
+
+	oldTexts := texts(oldResp.Chunks)
+	newTexts := texts(newResp.Chunks)
+	oldUsed := make([]bool, len(oldResp.Chunks))
+	newUsed := make([]bool, len(newResp.Chunks))
+
+	pair := func(i, j int, iou, sim float64) {
+		oldUsed[i], newUsed[j] = true, true
+
+		oc, nc := &oldResp.Chunks[i], &newResp.Chunks[j]
+		change := ChunkChange{
+			Kind:        ChangeUnchanged,
+			Old:         oc,
+			New:         nc,
+			IoU:         iou,
+			Similarity:  sim,
+			TypeChanged: oc.Type != nc.Type,
+		}
+		// Text only drives alignment; markdown-only edits such as heading
+		// levels, emphasis or link targets are changes too
+		if oc.Markdown != nc.Markdown {
+			change.TextDiff = Lines(oc.Markdown, nc.Markdown)
+			change.CellChanges = compareTables(oc.Markdown, nc.Markdown)
+		}
+		if change.TypeChanged || change.TextDiff != nil {
+			change.Kind = ChangeModified
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	// Identical text on the same page is aligned first, picking the best box
+	// overlap among duplicates, so fuzzy matching only sees what is left
+	type textKey struct {
+		page int
+		text string
+	}
+	byText := make(map[textKey][]int)
+	for i, oc := range oldResp.Chunks {
+		if oldTexts[i] != "" {
+			k := textKey{oc.Grounding.Page, oldTexts[i]}
+			byText[k] = append(byText[k], i)
+		}
+	}
+	for j, nc := range newResp.Chunks {
+		if newTexts[j] == "" {
+			continue
+		}
+		best, bestIoU := -1, -1.0
+		for _, i := range byText[textKey{nc.Grounding.Page, newTexts[j]}] {
+			if oldUsed[i] {
+				continue
+			}
+			if iou := oldResp.Chunks[i].Grounding.Box.IoU(nc.Grounding.Box); iou > bestIoU {
+				best, bestIoU = i, iou
+			}
+		}
+		if best >= 0 {
+			pair(best, j, bestIoU, 1)
+		}
+	}
+
+	// The remaining chunks are compared fuzzily, but only against chunks at a
+	// nearby position on the same page, which keeps large documents tractable
+	type candidate struct {
+		i, j     int
+		iou, sim float64
+		score    float64
+	}
+	var candidates []candidate
+	oldPages := byPage(oldResp.Chunks)
+	newPages := byPage(newResp.Chunks)
+	for page, olds := range oldPages {
+		news := newPages[page]
+		for oi, i := range olds {
+			if oldUsed[i] {
+				continue
+			}
+			for nj := max(0, oi-opts.Window); nj < min(len(news), oi+opts.Window+1); nj++ {
+				j := news[nj]
+				if newUsed[j] {
+					continue
+				}
+				iou := oldResp.Chunks[i].Grounding.Box.IoU(newResp.Chunks[j].Grounding.Box)
+				if iou < opts.MinIoU && maxSimilarity(oldTexts[i], newTexts[j]) < opts.MinSimilarity {
+					continue
+				}
+				sim := strdist.Similarity(oldTexts[i], newTexts[j])
+				if iou < opts.MinIoU && sim < opts.MinSimilarity {
+					continue
+				}
+				candidates = append(candidates, candidate{i: i, j: j, iou: iou, sim: sim, score: iou + sim})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+		if ca.score != cb.score {
+			return ca.score > cb.score
+		}
+		// Map iteration order is random, so break ties deterministically
+		if ca.i != cb.i {
+			return ca.i < cb.i
+		}
+		return ca.j < cb.j
+	})
+	for _, c := range candidates {
+		if !oldUsed[c.i] && !newUsed[c.j] {
+			pair(c.i, c.j, c.iou, c.sim)
+		}
+	}
+
+	for i := range oldResp.Chunks {
+		if !oldUsed[i] {
+			report.Changes = append(report.Changes, ChunkChange{Kind: ChangeRemoved, Old: &oldResp.Chunks[i]})
+		}
+	}
+	for j := range newResp.Chunks {
+		if !newUsed[j] {
+			report.Changes = append(report.Changes, ChunkChange{Kind: ChangeAdded, New: &newResp.Chunks[j]})
+		}
+	}
+
+	sort.SliceStable(report.Changes, func(a, b int) bool {
+		ca, cb := report.Changes[a], report.Changes[b]
+		if ca.Page() != cb.Page() {
+			return ca.Page() < cb.Page()
+		}
+		return top(ca) < top(cb)
+	})
+	for _, c := range report.Changes {
+		switch c.Kind {
+		case ChangeUnchanged:
+			report.Unchanged++
+		case ChangeAdded:
+			report.Added++
+		case ChangeRemoved:
+			report.Removed++
+		case ChangeModified:
+			report.Modified++
+		}
+	}
+	return report
+}
+
+// byPage groups chunk indices by page, in document order
+func byPage(chunks []landingai.ParseChunk) map[int][]int {
+	pages := make(map[int][]int)
+	for i, c := range chunks {
+		pages[c.Grounding.Page] = append(pages[c.Grounding.Page], i)
+	}
+	return pages
+}
+
+// maxSimilarity is an upper bound of strdist.Similarity from the lengths alone
+func maxSimilarity(a, b string) float64 {
+	la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+	n := max(la, lb)
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(max(la, lb)-min(la, lb))/float64(n)
+}
+
+func top(c ChunkChange) float64 {
+	if c.New != nil {
+		return c.New.Grounding.Box.Top
+	}
+	return c.Old.Grounding.Box.Top
+}
+
+func texts(chunks []landingai.ParseChunk) []string {
+	out := make([]string, len(chunks))
+	for i, c := range chunks {
+		out[i] = c.Text()
+	}
+	return out
+}
+
+func modelOf(resp *landingai.ParseResponse) string {
+	if resp.Metadata.Version == nil {
+		return ""
+	}
+	return *resp.Metadata.Version
+}
+
+// compareTables compares the tables of two chunks cell by cell
+func compareTables(oldMD, newMD string) []CellChange {
+	oldTables := markdown.ParseTables(oldMD)
+	newTables := markdown.ParseTables(newMD)
+
+	var changes []CellChange
+	for t := 0; t < max(len(oldTables), len(newTables)); t++ {
+		var oldRows, newRows [][]markdown.Cell
+		if t < len(oldTables) {
+			oldRows = oldTables[t].Rows
+		}
+		if t < len(newTables) {
+			newRows = newTables[t].Rows
+		}
+		for r := 0; r < max(len(oldRows), len(newRows)); r++ {
+			oldRow, newRow := rowAt(oldRows, r), rowAt(newRows, r)
+			for c := 0; c < max(len(oldRow), len(newRow)); c++ {
+				o, n := cellAt(oldRow, c), cellAt(newRow, c)
+				if o != n {
+					changes = append(changes, CellChange{Table: t, Row: r, Col: c, Old: o, New: n})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+func rowAt(rows [][]markdown.Cell, i int) []markdown.Cell {
+	if i < len(rows) {
+		return rows[i]
+	}
+	return nil
+}
+
+func cellAt(row []markdown.Cell, i int) string {
+	if i < len(row) {
+		return row[i].Text
+	}
+	return ""
+}
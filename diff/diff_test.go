@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func box(left, top, right, bottom float64) landingai.ParseGroundingBox {
+	return landingai.ParseGroundingBox{Left: left, Top: top, Right: right, Bottom: bottom}
+}
+
+func chunk(id, typ, md string, b landingai.ParseGroundingBox) landingai.ParseChunk {
+	return landingai.ParseChunk{ID: id, Type: typ, Markdown: md, Grounding: landingai.ParseGrounding{Box: b}}
+}
+
+func TestCompare(t *testing.T) {
+	oldVersion, newVersion := "dpt-2-20250919", "dpt-2-20251103"
+	oldResp := &landingai.ParseResponse{
+		Metadata: landingai.ParseMetadata{Version: &oldVersion},
+		Chunks: []landingai.ParseChunk{
+			chunk("o1", "text", "# Title", box(0.1, 0.0, 0.9, 0.1)),
+			chunk("o2", "table", "| a | b |\n|---|---|\n| 1 | 2 |", box(0.1, 0.2, 0.9, 0.5)),
+			chunk("o3", "text", "Signature", box(0.1, 0.8, 0.3, 0.9)),
+			chunk("o4", "text", "Footnote", box(0.1, 0.95, 0.9, 1.0)),
+		},
+	}
+	newResp := &landingai.ParseResponse{
+		Metadata: landingai.ParseMetadata{Version: &newVersion},
+		Chunks: []landingai.ParseChunk{
+			chunk("n1", "text", "# Title", box(0.1, 0.0, 0.9, 0.11)),
+			chunk("n2", "table", "| a | b |\n|---|---|\n| 1 | 3 |", box(0.1, 0.2, 0.9, 0.52)),
+			chunk("n3", "attestation", "Signature", box(0.1, 0.8, 0.3, 0.9)),
+			chunk("n5", "text", "Stamp", box(0.6, 0.6, 0.9, 0.7)),
+		},
+	}
+
+	report := Compare(oldResp, newResp, Options{})
+
+	if report.Unchanged != 1 || report.Modified != 2 || report.Added != 1 || report.Removed != 1 {
+		t.Fatalf("report counts = %d unchanged, %d modified, %d added, %d removed",
+			report.Unchanged, report.Modified, report.Added, report.Removed)
+	}
+
+	var table, typed *ChunkChange
+	for i, c := range report.Changes {
+		if c.Kind != ChangeModified {
+			continue
+		}
+		switch c.New.ID {
+		case "n2":
+			table = &report.Changes[i]
+		case "n3":
+			typed = &report.Changes[i]
+		}
+	}
+	if table == nil || len(table.CellChanges) != 1 {
+		t.Fatalf("table change = %+v", table)
+	}
+	if cell := table.CellChanges[0]; cell.Row != 1 || cell.Col != 1 || cell.Old != "2" || cell.New != "3" {
+		t.Errorf("CellChanges[0] = %+v", cell)
+	}
+	if typed == nil || !typed.TypeChanged || typed.TextDiff != nil {
+		t.Errorf("type change = %+v", typed)
+	}
+
+	text := report.String()
+	for _, want := range []string{"--- dpt-2-20250919", "type: text -> attestation", `"2" -> "3"`, "added text chunk n5", "removed text chunk o4"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestCompare_MarkdownOnlyChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+	}{
+		{name: "heading level", old: "# Terms", new: "## Terms"},
+		{name: "emphasis", old: "Pay **now**", new: "Pay now"},
+		{name: "link target", old: "[portal](https://a.example)", new: "[portal](https://b.example)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(
+				&landingai.ParseResponse{Chunks: []landingai.ParseChunk{chunk("o1", "text", tt.old, box(0.1, 0.1, 0.9, 0.2))}},
+				&landingai.ParseResponse{Chunks: []landingai.ParseChunk{chunk("n1", "text", tt.new, box(0.1, 0.1, 0.9, 0.2))}},
+				Options{},
+			)
+			if report.Modified != 1 || len(report.Changes) != 1 {
+				t.Fatalf("report = %+v, want one modified chunk", report)
+			}
+			want := []LineEdit{{Op: OpDelete, Text: tt.old}, {Op: OpInsert, Text: tt.new}}
+			if got := report.Changes[0].TextDiff; !slices.Equal(got, want) {
+				t.Errorf("TextDiff = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCompare_LargeDocuments(t *testing.T) {
+	const n = 1000
+	body := strings.Repeat("lorem ipsum dolor sit amet ", 8)
+	oldResp, newResp := &landingai.ParseResponse{}, &landingai.ParseResponse{}
+	for i := 0; i < n; i++ {
+		b := box(0.1, float64(i)/n, 0.9, float64(i+1)/n)
+		text := fmt.Sprintf("Paragraph %d: %s", i, body)
+		oldResp.Chunks = append(oldResp.Chunks, chunk(fmt.Sprintf("o%d", i), "text", text, b))
+		if i%10 == 0 {
+			text += " (revised)"
+		}
+		newResp.Chunks = append(newResp.Chunks, chunk(fmt.Sprintf("n%d", i), "text", text, b))
+	}
+
+	start := time.Now()
+	report := Compare(oldResp, newResp, Options{})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Compare() took %s", elapsed)
+	}
+	if report.Unchanged != 900 || report.Modified != 100 || report.Added != 0 || report.Removed != 0 {
+		t.Errorf("report counts = %d unchanged, %d modified, %d added, %d removed",
+			report.Unchanged, report.Modified, report.Added, report.Removed)
+	}
+	for _, c := range report.Changes {
+		if c.Kind == ChangeModified && c.Old.ID[1:] != c.New.ID[1:] {
+			t.Errorf("aligned %s with %s", c.Old.ID, c.New.ID)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	edits := Lines("a\nb\nc", "a\nc\nd")
+	var got []string
+	for _, e := range edits {
+		got = append(got, string(e.Op)+e.Text)
+	}
+	if want := " a,-b, c,+d"; strings.Join(got, ",") != want {
+		t.Errorf("Lines() = %q, want %q", strings.Join(got, ","), want)
+	}
+}
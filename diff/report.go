@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes a human-readable summary of the report to w.
+// Unchanged chunks are omitted.
+func (r *Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	if r.OldModel != "" || r.NewModel != "" {
+		fmt.Fprintf(&sb, "--- %s\n+++ %s\n", orUnknown(r.OldModel), orUnknown(r.NewModel))
+	}
+	fmt.Fprintf(&sb, "%d added, %d removed, %d modified, %d unchanged\n",
+		r.Added, r.Removed, r.Modified, r.Unchanged)
+
+	for _, c := range r.Changes {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&sb, "\n[page %d] added %s chunk %s\n", c.Page(), c.New.Type, c.New.ID)
+			writeIndented(&sb, "+ ", c.New.Text())
+		case ChangeRemoved:
+			fmt.Fprintf(&sb, "\n[page %d] removed %s chunk %s\n", c.Page(), c.Old.Type, c.Old.ID)
+			writeIndented(&sb, "- ", c.Old.Text())
+		case ChangeModified:
+			fmt.Fprintf(&sb, "\n[page %d] modified chunk %s -> %s (IoU %.2f, similarity %.2f)\n",
+				c.Page(), c.Old.ID, c.New.ID, c.IoU, c.Similarity)
+			if c.TypeChanged {
+				fmt.Fprintf(&sb, "  type: %s -> %s\n", c.Old.Type, c.New.Type)
+			}
+			for _, cell := range c.CellChanges {
+				fmt.Fprintf(&sb, "  table %d cell (%d,%d): %q -> %q\n", cell.Table, cell.Row, cell.Col, cell.Old, cell.New)
+			}
+			if len(c.CellChanges) == 0 {
+				for _, e := range c.TextDiff {
+					if e.Op != OpEqual {
+						fmt.Fprintf(&sb, "  %s %s\n", e.Op, e.Text)
+					}
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// String returns the human-readable summary written by WriteText
+func (r *Report) String() string {
+	var sb strings.Builder
+	_ = r.WriteText(&sb)
+	return sb.String()
+}
+
+func writeIndented(sb *strings.Builder, prefix, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString("  " + prefix + line + "\n")
+	}
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "(unknown model)"
+	}
+	return s
+}
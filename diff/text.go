@@ -0,0 +1,63 @@
+package diff
+
+import "strings"
+
+// EditOp is a line diff operation
+type EditOp string
+
+const (
+	OpEqual  EditOp = " "
+	OpDelete EditOp = "-"
+	OpInsert EditOp = "+"
+)
+
+// LineEdit is a single line of a text diff
+type LineEdit struct {
+	Op   EditOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns a line diff between old and new based on their longest common subsequence
+func Lines(oldText, newText string) []LineEdit {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []LineEdit
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, LineEdit{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, LineEdit{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			edits = append(edits, LineEdit{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, LineEdit{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, LineEdit{Op: OpInsert, Text: b[j]})
+	}
+	return edits
+}
@@ -1,20 +1,102 @@
 package landingai
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is when a request was rejected because the circuit breaker is open
+var ErrCircuitOpen = errors.New("landingai: circuit breaker is open")
+
+// Sentinel errors matched by errors.Is against *APIError, *ValidationErrors and *TransportError
+var (
+	// ErrRateLimited matches 429 responses
+	ErrRateLimited = errors.New("landingai: rate limited")
+	// ErrUnauthorized matches 401 responses
+	ErrUnauthorized = errors.New("landingai: unauthorized")
+	// ErrInsufficientCredits matches 402 responses
+	ErrInsufficientCredits = errors.New("landingai: insufficient credits")
+	// ErrTimeout matches 504 responses and transport timeouts, including context deadlines
+	ErrTimeout = errors.New("landingai: timeout")
+	// ErrValidation matches 422 responses
+	ErrValidation = errors.New("landingai: validation failed")
+)
+
+// ErrorContext identifies the request an error belongs to
+type ErrorContext struct {
+	// RequestID is the API request ID from the response headers, if any
+	RequestID string
+	// JobID is the parse job ID from the error body, if any
+	JobID string
+	// Attempt is the 1-based attempt that failed
+	Attempt int
+	// Tags are the tags set on the request with WithTags
+	Tags map[string]string
+}
+
+// suffix formats the context for error messages
+func (c ErrorContext) suffix() string {
+	var parts []string
+	if c.RequestID != "" {
+		parts = append(parts, "request "+c.RequestID)
+	}
+	if c.JobID != "" {
+		parts = append(parts, "job "+c.JobID)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// IsRetryable reports whether err is worth retrying: rate limits, server
+// errors, gateway timeouts, transport failures and open circuits
+func IsRetryable(err error) bool {
+	var r interface{ Retryable() bool }
+	return errors.As(err, &r) && r.Retryable()
+}
 
 // APIError represents an error returned by the Landing AI API
 type APIError struct {
 	StatusCode int
 	Message    string
 	Detail     interface{}
+	ErrorContext
 }
 
 // Error implements the error interface
 func (e *APIError) Error() string {
 	if e.Detail != nil {
-		return fmt.Sprintf("Landing AI API error (status %d): %s - %v", e.StatusCode, e.Message, e.Detail)
+		return fmt.Sprintf("Landing AI API error (status %d): %s - %v%s", e.StatusCode, e.Message, e.Detail, e.suffix())
 	}
-	return fmt.Sprintf("Landing AI API error (status %d): %s", e.StatusCode, e.Message)
+	return fmt.Sprintf("Landing AI API error (status %d): %s%s", e.StatusCode, e.Message, e.suffix())
+}
+
+// Is matches the sentinel error for the status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.IsRateLimited()
+	case ErrUnauthorized:
+		return e.IsUnauthorized()
+	case ErrInsufficientCredits:
+		return e.IsPaymentRequired()
+	case ErrTimeout:
+		return e.IsTimeout()
+	case ErrValidation:
+		return e.IsValidationError()
+	}
+	return false
+}
+
+// Retryable reports whether the request may succeed if sent again
+func (e *APIError) Retryable() bool {
+	return e.IsRateLimited() || e.IsServerError()
 }
 
 // IsUnauthorized returns true if the error is due to invalid authentication
@@ -62,17 +144,214 @@ type ValidationError struct {
 	Location []interface{} `json:"loc"`
 	Message  string        `json:"msg"`
 	Type     string        `json:"type"`
+
+	// Method is the ParseRequestBuilder method that set the failing field,
+	// e.g. "WithModel", or empty if the field does not map to one
+	Method string `json:"-"`
+}
+
+// FieldPath is a decoded validation error location
+type FieldPath struct {
+	// In is where the field was sent, e.g. "body" or "query", if the API reported it
+	In string
+	// Fields is the path to the field; array indexes are formatted as numbers
+	Fields []string
+}
+
+// Field returns the top-level field name, e.g. "model"
+func (p FieldPath) Field() string {
+	if len(p.Fields) == 0 {
+		return ""
+	}
+	return p.Fields[0]
+}
+
+// String returns the fields joined with dots, e.g. "model" or "chunks.0.type"
+func (p FieldPath) String() string {
+	return strings.Join(p.Fields, ".")
+}
+
+// Path decodes the location into a FieldPath
+func (e ValidationError) Path() FieldPath {
+	var p FieldPath
+	for i, loc := range e.Location {
+		var s string
+		switch v := loc.(type) {
+		case string:
+			s = v
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			s = fmt.Sprint(v)
+		}
+		if i == 0 && len(e.Location) > 1 && (s == "body" || s == "query" || s == "path" || s == "header") {
+			p.In = s
+			continue
+		}
+		p.Fields = append(p.Fields, s)
+	}
+	return p
+}
+
+// Field returns the location without its "body" or "query" prefix, joined with dots
+func (e ValidationError) Field() string {
+	return e.Path().String()
+}
+
+// Error implements the error interface, prefixed with the builder method or field
+func (e ValidationError) Error() string {
+	switch {
+	case e.Method != "":
+		return e.Method + ": " + e.Message
+	case e.Field() != "":
+		return e.Field() + ": " + e.Message
+	}
+	return e.Message
+}
+
+// builderMethods maps API request fields to the ParseRequestBuilder methods that set them
+var builderMethods = map[string]string{
+	"document":     "WithFile",
+	"document_url": "WithURL",
+	"model":        "WithModel",
+	"split":        "WithSplit",
+}
+
+// BuilderMethod returns the ParseRequestBuilder method that sets an API request
+// field, e.g. "WithModel" for "model". The document field maps to WithFile,
+// though WithFileData, WithSource and WithSourceURI set it too.
+func BuilderMethod(field string) (string, bool) {
+	method, ok := builderMethods[field]
+	return method, ok
 }
 
 // ValidationErrors represents a collection of validation errors
 type ValidationErrors struct {
-	Detail []ValidationError `json:"detail"`
+	Detail       []ValidationError `json:"detail"`
+	ErrorContext `json:"-"`
 }
 
-// Error implements the error interface
+// Error implements the error interface, listing every failing field
 func (v *ValidationErrors) Error() string {
 	if len(v.Detail) == 0 {
-		return "validation error"
+		return "validation error" + v.suffix()
+	}
+	msgs := make([]string, len(v.Detail))
+	for i, d := range v.Detail {
+		msgs[i] = d.Error()
+	}
+	return fmt.Sprintf("validation error: %s%s", strings.Join(msgs, "; "), v.suffix())
+}
+
+// FieldError returns the first error for a field, matched by top-level name
+// ("model") or full path ("chunks.0.type"), or nil if the field is valid
+func (v *ValidationErrors) FieldError(name string) *ValidationError {
+	for i, d := range v.Detail {
+		path := d.Path()
+		if path.Field() == name || path.String() == name {
+			return &v.Detail[i]
+		}
+	}
+	return nil
+}
+
+// Is matches ErrValidation
+func (v *ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Retryable always returns false: the same request fails the same way
+func (v *ValidationErrors) Retryable() bool {
+	return false
+}
+
+// Operations of the API exchange reported in TransportError.Op
+const (
+	opExecuteRequest   = "execute request"
+	opReadResponseBody = "read response body"
+)
+
+// TransportError is returned when the request could not be sent or the
+// response could not be read, e.g. connection failures and timeouts
+type TransportError struct {
+	// Op is the failed operation, e.g. "execute request"
+	Op  string
+	Err error
+	ErrorContext
+}
+
+// Error implements the error interface
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("failed to %s: %v%s", e.Op, e.Err, e.suffix())
+}
+
+// Unwrap returns the underlying error
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrTimeout for deadlines and network timeouts
+func (e *TransportError) Is(target error) bool {
+	return target == ErrTimeout && e.timeout()
+}
+
+func (e *TransportError) timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
 	}
-	return fmt.Sprintf("validation error: %s", v.Detail[0].Message)
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// Retryable reports whether the request may succeed if sent again.
+// Cancelled requests are not retryable.
+func (e *TransportError) Retryable() bool {
+	return !errors.Is(e.Err, context.Canceled)
+}
+
+// CapabilityError is returned when the chosen model lacks features the request depends on
+type CapabilityError struct {
+	Model          Model
+	ChunkTypes     []ChunkType
+	GroundingTypes []GroundingType
+	Split          bool
+}
+
+// Error implements the error interface
+func (e *CapabilityError) Error() string {
+	var missing []string
+	for _, t := range e.ChunkTypes {
+		missing = append(missing, fmt.Sprintf("chunk type %q", t))
+	}
+	for _, t := range e.GroundingTypes {
+		missing = append(missing, fmt.Sprintf("grounding type %q", t))
+	}
+	if e.Split {
+		missing = append(missing, "split")
+	}
+	return fmt.Sprintf("model %q does not support %s", e.Model, strings.Join(missing, ", "))
+}
+
+// CircuitOpenError is returned without contacting the API while the circuit breaker is open
+type CircuitOpenError struct {
+	// RetryAfter is the time until the breaker lets a probe request through
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v: retry after %s", ErrCircuitOpen, e.RetryAfter.Round(time.Millisecond))
+	}
+	return ErrCircuitOpen.Error()
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// Retryable always returns true: the breaker lets requests through after RetryAfter
+func (e *CircuitOpenError) Retryable() bool {
+	return true
 }
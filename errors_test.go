@@ -0,0 +1,173 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		sentinels []error
+		retryable bool
+	}{
+		{name: "rate limited", err: &APIError{StatusCode: StatusTooManyRequests}, sentinels: []error{ErrRateLimited}, retryable: true},
+		{name: "unauthorized", err: &APIError{StatusCode: StatusUnauthorized}, sentinels: []error{ErrUnauthorized}},
+		{name: "insufficient credits", err: &APIError{StatusCode: StatusPaymentRequired}, sentinels: []error{ErrInsufficientCredits}},
+		{name: "gateway timeout", err: &APIError{StatusCode: StatusGatewayTimeout}, sentinels: []error{ErrTimeout}, retryable: true},
+		{name: "server error", err: &APIError{StatusCode: StatusInternalServerError}, retryable: true},
+		{name: "validation", err: &ValidationErrors{}, sentinels: []error{ErrValidation}},
+		{name: "deadline", err: &TransportError{Op: "execute request", Err: context.DeadlineExceeded}, sentinels: []error{ErrTimeout}, retryable: true},
+		{name: "cancelled", err: &TransportError{Op: "execute request", Err: context.Canceled}},
+		{name: "circuit open", err: &CircuitOpenError{}, sentinels: []error{ErrCircuitOpen}, retryable: true},
+	}
+	all := []error{ErrRateLimited, ErrUnauthorized, ErrInsufficientCredits, ErrTimeout, ErrValidation, ErrCircuitOpen}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := errors.Join(errors.New("context"), tt.err)
+			for _, sentinel := range all {
+				want := false
+				for _, s := range tt.sentinels {
+					want = want || s == sentinel
+				}
+				if got := errors.Is(wrapped, sentinel); got != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", sentinel, got, want)
+				}
+			}
+			if got := IsRetryable(wrapped); got != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-42")
+		w.WriteHeader(StatusPaymentRequired)
+		w.Write([]byte(`{"detail":"Insufficient credits","job_id":"job-7"}`))
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	_, err := client.Parse(context.Background()).WithURL("https://example.com/doc.pdf").Do()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Do() error = %v, want *APIError", err)
+	}
+	if apiErr.RequestID != "req-42" || apiErr.JobID != "job-7" || apiErr.Attempt != 1 {
+		t.Errorf("context = %+v", apiErr.ErrorContext)
+	}
+	if !errors.Is(err, ErrInsufficientCredits) {
+		t.Error("errors.Is(ErrInsufficientCredits) = false")
+	}
+	if !strings.Contains(err.Error(), "request req-42") {
+		t.Errorf("Error() = %q, want the request ID", err.Error())
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithTimeout(20*time.Millisecond))
+
+	_, err := client.Parse(context.Background()).WithURL("https://example.com/doc.pdf").Do()
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Fatalf("Do() error = %v, want *TransportError", err)
+	}
+	if transportErr.Attempt != 1 || !errors.Is(err, ErrTimeout) || !IsRetryable(err) {
+		t.Errorf("error = %v (attempt %d), want a retryable timeout", err, transportErr.Attempt)
+	}
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	err := &ValidationErrors{Detail: []ValidationError{
+		{Location: []interface{}{"body", "model"}, Message: "unknown model"},
+		{Location: []interface{}{"body", "split"}, Message: "invalid split"},
+		{Message: "document is required"},
+	}}
+	want := "validation error: model: unknown model; split: invalid split; document is required"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestValidationError_Path(t *testing.T) {
+	tests := []struct {
+		loc    []interface{}
+		in     string
+		fields string
+	}{
+		{loc: []interface{}{"body", "model"}, in: "body", fields: "model"},
+		{loc: []interface{}{"query", "options", float64(2), "name"}, in: "query", fields: "options.2.name"},
+		{loc: []interface{}{"model"}, fields: "model"},
+		{loc: nil, fields: ""},
+	}
+	for _, tt := range tests {
+		path := ValidationError{Location: tt.loc}.Path()
+		if path.In != tt.in || path.String() != tt.fields {
+			t.Errorf("Path(%v) = %q %q, want %q %q", tt.loc, path.In, path.String(), tt.in, tt.fields)
+		}
+	}
+}
+
+func TestValidationErrors_FieldError(t *testing.T) {
+	err := &ValidationErrors{Detail: []ValidationError{
+		{Location: []interface{}{"body", "model"}, Message: "unknown model"},
+		{Location: []interface{}{"body", "options", float64(0), "type"}, Message: "invalid type"},
+	}}
+	if fe := err.FieldError("model"); fe == nil || fe.Message != "unknown model" {
+		t.Errorf("FieldError(model) = %v", fe)
+	}
+	if fe := err.FieldError("options.0.type"); fe == nil || fe.Message != "invalid type" {
+		t.Errorf("FieldError(options.0.type) = %v", fe)
+	}
+	if fe := err.FieldError("options"); fe == nil {
+		t.Error("FieldError(options) = nil, want the nested error")
+	}
+	if fe := err.FieldError("split"); fe != nil {
+		t.Errorf("FieldError(split) = %v, want nil", fe)
+	}
+}
+
+func TestValidationErrors_BuilderMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(StatusUnprocessableEntity)
+		w.Write([]byte(`{"detail":[
+			{"loc":["body","model"],"msg":"unknown model 'dpt-3'","type":"value_error"},
+			{"loc":["body","document"],"msg":"unsupported file type","type":"value_error"},
+			{"loc":["body","pages"],"msg":"extra fields not permitted","type":"value_error"}
+		]}`))
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	_, err := client.Parse(context.Background()).
+		WithFileData([]byte("data"), "doc.xyz").
+		WithModel("dpt-3").
+		Do()
+	var valErr *ValidationErrors
+	if !errors.As(err, &valErr) {
+		t.Fatalf("Do() error = %v, want *ValidationErrors", err)
+	}
+	if got := valErr.FieldError("model").Method; got != "WithModel" {
+		t.Errorf("model method = %q, want WithModel", got)
+	}
+	if got := valErr.FieldError("document").Method; got != "WithFileData" {
+		t.Errorf("document method = %q, want WithFileData", got)
+	}
+	want := "validation error: WithModel: unknown model 'dpt-3'; WithFileData: unsupported file type; pages: extra fields not permitted"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,151 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+// TruthSuffix is the file suffix of ground truth files in a corpus directory
+const TruthSuffix = ".truth.json"
+
+// Case is a ground truth document paired with the response to score
+type Case struct {
+	Name     string
+	Truth    *GroundTruth
+	Response *landingai.ParseResponse
+}
+
+// LoadCorpus loads every <name>.truth.json in truthDir and pairs it with
+// <name>.json in predDir. Predictions may be plain ParseResponse JSON or
+// results written by landingai.SaveResult.
+func LoadCorpus(truthDir, predDir string) ([]Case, error) {
+	paths, err := filepath.Glob(filepath.Join(truthDir, "*"+TruthSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var cases []Case
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), TruthSuffix)
+
+		var truth GroundTruth
+		if err := readJSON(path, &truth); err != nil {
+			return nil, err
+		}
+		if truth.Document == "" {
+			truth.Document = name
+		}
+
+		resp, err := LoadResponse(filepath.Join(predDir, name+".json"))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, Case{Name: name, Truth: &truth, Response: resp})
+	}
+	return cases, nil
+}
+
+// LoadResponse reads a ParseResponse from a file holding either plain response
+// JSON or a result written by landingai.SaveResult
+func LoadResponse(path string) (*landingai.ParseResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open prediction: %w", err)
+	}
+	defer f.Close()
+
+	if stored, err := landingai.LoadResult(f); err == nil {
+		return stored.Response, nil
+	}
+
+	var resp landingai.ParseResponse
+	if err := readJSON(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Summary aggregates results across a corpus
+type Summary struct {
+	Documents []*Result `json:"documents"`
+	Total     *Result   `json:"total"`
+}
+
+// Run evaluates every case and aggregates the results
+func Run(cases []Case, opts Options) *Summary {
+	summary := &Summary{Total: &Result{Document: "TOTAL"}}
+	for _, c := range cases {
+		result := Evaluate(c.Truth, c.Response, opts)
+		summary.Documents = append(summary.Documents, result)
+		summary.Total.add(result)
+	}
+	return summary
+}
+
+// WriteTable writes per-type detection scores for the corpus followed by
+// per-document scores, as aligned text columns
+func (s *Summary) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tTP\tFP\tFN\tPRECISION\tRECALL\tF1")
+	types := make([]string, 0, len(s.Total.Types))
+	for typ := range s.Total.Types {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		writeCounts(tw, typ, *s.Total.Types[typ])
+	}
+	writeCounts(tw, "all", s.Total.Overall())
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DOCUMENT\tPRECISION\tRECALL\tF1\tCER\tTABLE ACC\tFIELD ACC")
+	for _, r := range append(s.Documents, s.Total) {
+		overall := r.Overall()
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%.3f\t%s\t%s\t%s\n",
+			r.Document, overall.Precision(), overall.Recall(), overall.F1(),
+			optional(r.CharacterErrorRate(), r.TextLength),
+			optional(r.TableAccuracy(), r.TableCells),
+			optional(r.FieldAccuracy(), r.Fields),
+		)
+	}
+	return tw.Flush()
+}
+
+func writeCounts(w io.Writer, name string, c Counts) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.3f\t%.3f\t%.3f\n",
+		name, c.TruePositives, c.FalsePositives, c.FalseNegatives, c.Precision(), c.Recall(), c.F1())
+}
+
+// optional formats a score, or "-" when nothing was labeled for it
+func optional(score float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", score)
+}
@@ -0,0 +1,242 @@
+// Package eval scores ParseResponses against hand-labeled ground truth.
+//
+// Chunk detection is scored per chunk type with precision and recall at an IoU
+// threshold. Matched chunks are further scored on text edit distance and table
+// cell accuracy, and labeled fields are checked against extracted key-value pairs.
+package eval
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/youssefsiam38/landingai"
+	"github.com/youssefsiam38/landingai/internal/markdown"
+	"github.com/youssefsiam38/landingai/internal/strdist"
+)
+
+// DefaultIoUThreshold is the default minimum IoU for a predicted chunk to match a labeled one
+const DefaultIoUThreshold = 0.5
+
+// GroundTruth is the hand-labeled expected output for one document
+type GroundTruth struct {
+	Document string            `json:"document"`
+	Chunks   []TruthChunk      `json:"chunks"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+// TruthChunk is a labeled chunk. Text and Table are optional; when set, matched
+// predictions are scored on them.
+type TruthChunk struct {
+	Type  string                      `json:"type"`
+	Page  int                         `json:"page"`
+	Box   landingai.ParseGroundingBox `json:"box"`
+	Text  string                      `json:"text,omitempty"`
+	Table [][]string                  `json:"table,omitempty"`
+}
+
+// Options configures Evaluate
+type Options struct {
+	// IoUThreshold is the minimum IoU for a match (default DefaultIoUThreshold)
+	IoUThreshold float64
+}
+
+// Counts holds detection counts for one chunk type
+type Counts struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// Precision returns TP / (TP + FP), or 0 if nothing was predicted
+func (c Counts) Precision() float64 {
+	return ratio(c.TruePositives, c.TruePositives+c.FalsePositives)
+}
+
+// Recall returns TP / (TP + FN), or 0 if nothing was labeled
+func (c Counts) Recall() float64 {
+	return ratio(c.TruePositives, c.TruePositives+c.FalseNegatives)
+}
+
+// F1 returns the harmonic mean of precision and recall
+func (c Counts) F1() float64 {
+	p, r := c.Precision(), c.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+func (c *Counts) add(other Counts) {
+	c.TruePositives += other.TruePositives
+	c.FalsePositives += other.FalsePositives
+	c.FalseNegatives += other.FalseNegatives
+}
+
+// Result holds the scores for one document, or the totals across a corpus
+type Result struct {
+	Document string             `json:"document"`
+	Types    map[string]*Counts `json:"types"`
+
+	// TextDistance is the summed edit distance over matched chunks with labeled text
+	TextDistance int `json:"text_distance"`
+	// TextLength is the summed length in runes of the labeled text that was scored
+	TextLength int `json:"text_length"`
+
+	TableCells        int `json:"table_cells"`
+	TableCellsCorrect int `json:"table_cells_correct"`
+
+	Fields        int `json:"fields"`
+	FieldsCorrect int `json:"fields_correct"`
+}
+
+// Overall returns the detection counts summed over all chunk types
+func (r *Result) Overall() Counts {
+	var total Counts
+	for _, c := range r.Types {
+		total.add(*c)
+	}
+	return total
+}
+
+// CharacterErrorRate returns TextDistance / TextLength
+func (r *Result) CharacterErrorRate() float64 {
+	return ratio(r.TextDistance, r.TextLength)
+}
+
+// TableAccuracy returns the fraction of labeled table cells predicted exactly
+func (r *Result) TableAccuracy() float64 {
+	return ratio(r.TableCellsCorrect, r.TableCells)
+}
+
+// FieldAccuracy returns the fraction of labeled fields extracted with the expected value
+func (r *Result) FieldAccuracy() float64 {
+	return ratio(r.FieldsCorrect, r.Fields)
+}
+
+func (r *Result) counts(typ string) *Counts {
+	if r.Types == nil {
+		r.Types = make(map[string]*Counts)
+	}
+	c, ok := r.Types[typ]
+	if !ok {
+		c = &Counts{}
+		r.Types[typ] = c
+	}
+	return c
+}
+
+func (r *Result) add(other *Result) {
+	for typ, c := range other.Types {
+		r.counts(typ).add(*c)
+	}
+	r.TextDistance += other.TextDistance
+	r.TextLength += other.TextLength
+	r.TableCells += other.TableCells
+	r.TableCellsCorrect += other.TableCellsCorrect
+	r.Fields += other.Fields
+	r.FieldsCorrect += other.FieldsCorrect
+}
+
+// Evaluate scores a response against the ground truth for the same document
+func Evaluate(truth *GroundTruth, resp *landingai.ParseResponse, opts Options) *Result {
+	if opts.IoUThreshold <= 0 {
+		opts.IoUThreshold = DefaultIoUThreshold
+	}
+	result := &Result{Document: truth.Document}
+
+	type pair struct {
+		t, p int
+		iou  float64
+	}
+	var pairs []pair
+	for ti, tc := range truth.Chunks {
+		for pi, pc := range resp.Chunks {
+			if tc.Type != pc.Type || tc.Page != pc.Grounding.Page {
+				continue
+			}
+			if iou := tc.Box.IoU(pc.Grounding.Box); iou >= opts.IoUThreshold {
+				pairs = append(pairs, pair{t: ti, p: pi, iou: iou})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].iou > pairs[b].iou })
+
+	truthMatched := make([]bool, len(truth.Chunks))
+	predMatched := make([]bool, len(resp.Chunks))
+	for _, p := range pairs {
+		if truthMatched[p.t] || predMatched[p.p] {
+			continue
+		}
+		truthMatched[p.t], predMatched[p.p] = true, true
+		result.counts(truth.Chunks[p.t].Type).TruePositives++
+		result.scoreContent(truth.Chunks[p.t], resp.Chunks[p.p])
+	}
+	for i, tc := range truth.Chunks {
+		if !truthMatched[i] {
+			result.counts(tc.Type).FalseNegatives++
+			// Missed chunks count fully against text and table scores
+			if tc.Text != "" {
+				text := normalizeText(tc.Text)
+				result.TextDistance += len([]rune(text))
+				result.TextLength += len([]rune(text))
+			}
+			for _, row := range tc.Table {
+				result.TableCells += len(row)
+			}
+		}
+	}
+	for i, pc := range resp.Chunks {
+		if !predMatched[i] {
+			result.counts(pc.Type).FalsePositives++
+		}
+	}
+
+	if len(truth.Fields) > 0 {
+		kvs := resp.KeyValues()
+		for key, want := range truth.Fields {
+			result.Fields++
+			if kv, ok := kvs.Lookup(key); ok && normalizeText(kv.Value) == normalizeText(want) {
+				result.FieldsCorrect++
+			}
+		}
+	}
+	return result
+}
+
+// scoreContent scores the text and table content of a matched chunk
+func (r *Result) scoreContent(truth TruthChunk, pred landingai.ParseChunk) {
+	if truth.Text != "" {
+		want := normalizeText(truth.Text)
+		got := normalizeText(pred.Text())
+		r.TextDistance += strdist.Levenshtein(want, got)
+		r.TextLength += len([]rune(want))
+	}
+
+	if len(truth.Table) == 0 {
+		return
+	}
+	var rows [][]markdown.Cell
+	if tables := markdown.ParseTables(pred.Markdown); len(tables) > 0 {
+		rows = tables[0].Rows
+	}
+	for i, row := range truth.Table {
+		for j, want := range row {
+			r.TableCells++
+			if i < len(rows) && j < len(rows[i]) && normalizeText(rows[i][j].Text) == normalizeText(want) {
+				r.TableCellsCorrect++
+			}
+		}
+	}
+}
+
+// normalizeText collapses whitespace so layout differences are not scored as errors
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,97 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func testCase() (*GroundTruth, *landingai.ParseResponse) {
+	truth := &GroundTruth{
+		Document: "invoice",
+		Chunks: []TruthChunk{
+			{Type: "text", Box: landingai.ParseGroundingBox{Left: 0.1, Top: 0.1, Right: 0.9, Bottom: 0.2}, Text: "Invoice 42"},
+			{Type: "table", Box: landingai.ParseGroundingBox{Left: 0.1, Top: 0.3, Right: 0.9, Bottom: 0.6}, Table: [][]string{{"Item", "Qty"}, {"Widget", "3"}}},
+			{Type: "figure", Box: landingai.ParseGroundingBox{Left: 0.1, Top: 0.7, Right: 0.3, Bottom: 0.9}},
+		},
+		Fields: map[string]string{"total": "$42.00"},
+	}
+	resp := &landingai.ParseResponse{
+		Chunks: []landingai.ParseChunk{
+			{ID: "a", Type: "text", Markdown: "Invoice 43", Grounding: landingai.ParseGrounding{Box: landingai.ParseGroundingBox{Left: 0.1, Top: 0.1, Right: 0.9, Bottom: 0.21}}},
+			{ID: "b", Type: "table", Markdown: "| Item | Qty |\n|---|---|\n| Widget | 4 |", Grounding: landingai.ParseGrounding{Box: landingai.ParseGroundingBox{Left: 0.1, Top: 0.3, Right: 0.9, Bottom: 0.6}}},
+			{ID: "c", Type: "text", Markdown: "Total: $42.00", Grounding: landingai.ParseGrounding{Box: landingai.ParseGroundingBox{Left: 0.5, Top: 0.9, Right: 0.9, Bottom: 1.0}}},
+		},
+		Grounding: map[string]landingai.ParseResponseGrounding{
+			"c": {Type: landingai.GroundingTypeChunkKeyValue},
+		},
+	}
+	return truth, resp
+}
+
+func TestEvaluate(t *testing.T) {
+	truth, resp := testCase()
+	result := Evaluate(truth, resp, Options{})
+
+	text := result.Types["text"]
+	if text.TruePositives != 1 || text.FalsePositives != 1 || text.FalseNegatives != 0 {
+		t.Errorf("text counts = %+v", *text)
+	}
+	if text.Precision() != 0.5 || text.Recall() != 1 {
+		t.Errorf("text precision/recall = %v/%v", text.Precision(), text.Recall())
+	}
+	if figure := result.Types["figure"]; figure.FalseNegatives != 1 {
+		t.Errorf("figure counts = %+v", *figure)
+	}
+	if result.TextDistance != 1 || result.TextLength != 10 {
+		t.Errorf("text distance = %d/%d, want 1/10", result.TextDistance, result.TextLength)
+	}
+	if result.TableCells != 4 || result.TableCellsCorrect != 3 {
+		t.Errorf("table cells = %d/%d, want 3/4", result.TableCellsCorrect, result.TableCells)
+	}
+	if result.Fields != 1 || result.FieldsCorrect != 1 {
+		t.Errorf("fields = %d/%d, want 1/1", result.FieldsCorrect, result.Fields)
+	}
+}
+
+func TestRun_WriteTable(t *testing.T) {
+	dir := t.TempDir()
+	truth, resp := testCase()
+	writeJSON(t, filepath.Join(dir, "invoice"+TruthSuffix), truth)
+	writeJSON(t, filepath.Join(dir, "invoice.json"), resp)
+
+	cases, err := LoadCorpus(dir, dir)
+	if err != nil {
+		t.Fatalf("LoadCorpus() error = %v", err)
+	}
+	if len(cases) != 1 || cases[0].Name != "invoice" {
+		t.Fatalf("LoadCorpus() = %+v", cases)
+	}
+
+	summary := Run(cases, Options{})
+	var buf bytes.Buffer
+	if err := summary.WriteTable(&buf); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	for _, want := range []string{"PRECISION", "invoice", "TOTAL", "table"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteTable() missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
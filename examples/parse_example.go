@@ -92,7 +92,7 @@ func modelExample(apiKey string) {
 	// Parse with specific model version
 	result, err := client.Parse(ctx).
 		WithFile("document.pdf").
-		WithModel("dpt-2-latest").
+		WithModel(landingai.ModelDPT2Latest).
 		Do()
 
 	if err != nil {
@@ -0,0 +1,207 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// FailoverEndpoint is a client that a FailoverClient can route requests to
+type FailoverEndpoint struct {
+	// Name identifies the endpoint in logs and errors (default "<region>-<index>")
+	Name string
+	// Client sends the requests; its region is used for data-residency checks
+	Client *Client
+	// Priority orders endpoints, lowest first. Endpoints with equal priority keep their order.
+	Priority int
+}
+
+// EndpointHealth describes the health of a failover endpoint
+type EndpointHealth struct {
+	Name     string
+	Region   Region
+	State    CircuitState
+	Failures int
+}
+
+// FailoverClient sends parse requests to the first healthy endpoint in priority
+// order, failing over to the next one on server errors, gateway timeouts and
+// transport failures. Each endpoint has its own circuit breaker.
+type FailoverClient struct {
+	endpoints     []*failoverEndpoint
+	breakerConfig CircuitBreakerConfig
+	logger        *slog.Logger
+}
+
+type failoverEndpoint struct {
+	FailoverEndpoint
+	breaker *circuitBreaker
+}
+
+// FailoverOption is a function that configures a FailoverClient
+type FailoverOption func(*FailoverClient)
+
+// WithFailoverCircuitBreaker configures the per-endpoint circuit breakers
+func WithFailoverCircuitBreaker(config CircuitBreakerConfig) FailoverOption {
+	return func(f *FailoverClient) {
+		f.breakerConfig = config
+	}
+}
+
+// WithFailoverLogger sets the logger used to report failovers
+func WithFailoverLogger(logger *slog.Logger) FailoverOption {
+	return func(f *FailoverClient) {
+		f.logger = logger
+	}
+}
+
+// NewFailoverClient creates a client that fails over between the given endpoints
+func NewFailoverClient(endpoints []FailoverEndpoint, opts ...FailoverOption) (*FailoverClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one failover endpoint is required")
+	}
+
+	f := &FailoverClient{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	for i, ep := range endpoints {
+		if ep.Client == nil {
+			return nil, fmt.Errorf("failover endpoint %d has no client", i)
+		}
+		if ep.Name == "" {
+			ep.Name = fmt.Sprintf("%s-%d", ep.Client.Region(), i)
+		}
+		f.endpoints = append(f.endpoints, &failoverEndpoint{
+			FailoverEndpoint: ep,
+			breaker:          newCircuitBreaker(f.breakerConfig),
+		})
+	}
+	sort.SliceStable(f.endpoints, func(i, j int) bool {
+		return f.endpoints[i].Priority < f.endpoints[j].Priority
+	})
+	return f, nil
+}
+
+// FailoverCallOption configures a single FailoverClient.Parse call
+type FailoverCallOption func(*failoverCall)
+
+type failoverCall struct {
+	regions []Region
+}
+
+// WithResidency restricts the request to endpoints in the given regions, e.g.
+// to keep EU documents in the EU. The request fails if no such endpoint is healthy.
+func WithResidency(regions ...Region) FailoverCallOption {
+	return func(c *failoverCall) {
+		c.regions = append(c.regions, regions...)
+	}
+}
+
+// Parse sends a parse request configured by configure to the first healthy
+// endpoint, failing over on server errors, gateway timeouts and transport
+// failures of the API exchange. Other errors, such as validation errors and
+// failures to download or read the document, are returned immediately and do
+// not count against the endpoint.
+func (f *FailoverClient) Parse(ctx context.Context, configure func(*ParseRequestBuilder), opts ...FailoverCallOption) (*ParseResponse, error) {
+	var call failoverCall
+	for _, opt := range opts {
+		opt(&call)
+	}
+
+	failErr := &FailoverError{}
+	for _, ep := range f.endpoints {
+		if !call.allows(ep.Client.Region()) {
+			continue
+		}
+		ticket, ok, retryAfter := ep.breaker.allow()
+		if !ok {
+			failErr.add(ep.Name, &CircuitOpenError{RetryAfter: retryAfter})
+			continue
+		}
+
+		builder := ep.Client.Parse(ctx)
+		configure(builder)
+		resp, err := builder.Do()
+		// Uncounted outcomes, such as source errors, only release the ticket
+		ep.breaker.record(ticket, err)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil || !(isEndpointFailure(err) || errors.Is(err, ErrCircuitOpen)) {
+			return nil, err
+		}
+
+		failErr.add(ep.Name, err)
+		f.logger.Warn("landingai: endpoint failed, failing over", "endpoint", ep.Name, "error", err)
+	}
+
+	if len(failErr.Endpoints) == 0 {
+		return nil, fmt.Errorf("no failover endpoint in regions %v", call.regions)
+	}
+	return nil, failErr
+}
+
+func (c *failoverCall) allows(region Region) bool {
+	if len(c.regions) == 0 {
+		return true
+	}
+	for _, r := range c.regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// Health returns the health of each endpoint in priority order
+func (f *FailoverClient) Health() []EndpointHealth {
+	health := make([]EndpointHealth, 0, len(f.endpoints))
+	for _, ep := range f.endpoints {
+		state, failures := ep.breaker.snapshot()
+		health = append(health, EndpointHealth{
+			Name:     ep.Name,
+			Region:   ep.Client.Region(),
+			State:    state,
+			Failures: failures,
+		})
+	}
+	return health
+}
+
+// FailoverError is returned when every eligible endpoint failed or was unavailable
+type FailoverError struct {
+	Endpoints []string
+	Errors    []error
+}
+
+func (e *FailoverError) add(endpoint string, err error) {
+	e.Endpoints = append(e.Endpoints, endpoint)
+	e.Errors = append(e.Errors, err)
+}
+
+// Error implements the error interface
+func (e *FailoverError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %v", e.Endpoints[i], err)
+	}
+	return "all failover endpoints failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns the error from each endpoint, so errors.Is and errors.As see all of them
+func (e *FailoverError) Unwrap() []error {
+	return e.Errors
+}
+
+// Last returns the error from the last endpoint tried
+func (e *FailoverError) Last() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[len(e.Errors)-1]
+}
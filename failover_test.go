@@ -0,0 +1,124 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFailoverClient_Parse(t *testing.T) {
+	var usCalls, euCalls atomic.Int32
+	us := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usCalls.Add(1)
+		w.WriteHeader(StatusGatewayTimeout)
+	}))
+	defer us.Close()
+	eu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		euCalls.Add(1)
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer eu.Close()
+
+	fc, err := NewFailoverClient([]FailoverEndpoint{
+		{Name: "eu", Client: NewClient("eu-key", WithRegion(RegionEU), WithBaseURL(eu.URL)), Priority: 1},
+		{Name: "us", Client: NewClient("us-key", WithBaseURL(us.URL)), Priority: 0},
+	}, WithFailoverCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1}))
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+
+	configure := func(b *ParseRequestBuilder) { b.WithFileData([]byte("data"), "doc.pdf") }
+
+	resp, err := fc.Parse(context.Background(), configure)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if resp.Markdown != "ok" || usCalls.Load() != 1 || euCalls.Load() != 1 {
+		t.Errorf("Parse() = %q with %d US and %d EU calls", resp.Markdown, usCalls.Load(), euCalls.Load())
+	}
+
+	// The US circuit is now open, so the next request skips it
+	if _, err := fc.Parse(context.Background(), configure); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if usCalls.Load() != 1 {
+		t.Errorf("US endpoint called %d times with an open circuit", usCalls.Load())
+	}
+	if health := fc.Health(); health[0].Name != "us" || health[0].State != CircuitOpen {
+		t.Errorf("Health() = %+v", health)
+	}
+}
+
+func TestFailoverClient_Residency(t *testing.T) {
+	us := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markdown":"us"}`))
+	}))
+	defer us.Close()
+	eu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(StatusInternalServerError)
+	}))
+	defer eu.Close()
+
+	fc, err := NewFailoverClient([]FailoverEndpoint{
+		{Client: NewClient("eu-key", WithRegion(RegionEU), WithBaseURL(eu.URL))},
+		{Client: NewClient("us-key", WithBaseURL(us.URL))},
+	})
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+
+	_, err = fc.Parse(context.Background(), func(b *ParseRequestBuilder) {
+		b.WithFileData([]byte("data"), "doc.pdf")
+	}, WithResidency(RegionEU))
+
+	var failErr *FailoverError
+	if !errors.As(err, &failErr) || len(failErr.Endpoints) != 1 {
+		t.Fatalf("Parse() error = %v, want FailoverError from the EU endpoint only", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsServerError() {
+		t.Errorf("Parse() error = %v, want wrapped server error", err)
+	}
+}
+
+func TestFailoverClient_SourceErrorNotFailedOver(t *testing.T) {
+	var calls atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer api.Close()
+
+	fc, err := NewFailoverClient([]FailoverEndpoint{
+		{Name: "primary", Client: NewClient("key", WithBaseURL(api.URL))},
+		{Name: "secondary", Client: NewClient("key", WithBaseURL(api.URL)), Priority: 1},
+	}, WithFailoverCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts int
+	_, err = fc.Parse(context.Background(), func(b *ParseRequestBuilder) {
+		attempts++
+		b.WithSourceURI("http://127.0.0.1:1/doc.pdf")
+	})
+	if err == nil {
+		t.Fatal("Parse() succeeded with an unreachable document")
+	}
+	var failErr *FailoverError
+	if errors.As(err, &failErr) || attempts != 1 {
+		t.Errorf("Parse() error = %v after %d attempts, want the source error from one endpoint", err, attempts)
+	}
+	for _, h := range fc.Health() {
+		if h.State != CircuitClosed || h.Failures != 0 {
+			t.Errorf("Health() = %+v, want no failures", h)
+		}
+	}
+
+	if _, err := fc.Parse(context.Background(), func(b *ParseRequestBuilder) { b.WithFileData([]byte("%PDF"), "doc.pdf") }); err != nil || calls.Load() != 1 {
+		t.Errorf("Parse() error = %v with %d calls", err, calls.Load())
+	}
+}
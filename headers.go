@@ -0,0 +1,114 @@
+package landingai
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"runtime"
+	"sort"
+)
+
+// reservedHeaders are set by the SDK and cannot be overridden with WithHeader
+var reservedHeaders = map[string]bool{
+	"Authorization":      true,
+	"Content-Type":       true,
+	"Content-Length":     true,
+	IdempotencyKeyHeader: true,
+}
+
+// DefaultUserAgent returns the User-Agent sent when none is configured,
+// e.g. "landingai-go/0.1.0 (go1.24.0)"
+func DefaultUserAgent() string {
+	return fmt.Sprintf("landingai-go/%s (%s)", Version, runtime.Version())
+}
+
+// WithUserAgent sets the User-Agent header of every request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header to every request. Authorization, Content-Type,
+// Content-Length and Idempotency-Key are managed by the SDK and ignored.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithUserAgent overrides the client's User-Agent for this request
+func (b *ParseRequestBuilder) WithUserAgent(userAgent string) *ParseRequestBuilder {
+	return b.WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader sets a header for this request, replacing any value configured on
+// the client. Reserved headers are ignored as for the WithHeader client option.
+func (b *ParseRequestBuilder) WithHeader(key, value string) *ParseRequestBuilder {
+	if b.req.Header == nil {
+		b.req.Header = make(http.Header)
+	}
+	b.req.Header.Set(key, value)
+	return b
+}
+
+// WithTags labels the request, e.g. with a customer or pipeline name. Tags
+// are added to log records, returned in ResponseInfo.Tags and
+// ErrorContext.Tags for metrics and credit accounting, and merged with tags
+// set earlier. The parse API has no metadata field, so tags are not sent upstream.
+func (b *ParseRequestBuilder) WithTags(tags map[string]string) *ParseRequestBuilder {
+	if len(tags) == 0 {
+		return b
+	}
+	if b.req.Tags == nil {
+		b.req.Tags = make(map[string]string, len(tags))
+	}
+	maps.Copy(b.req.Tags, tags)
+	return b
+}
+
+// setHeaders applies the User-Agent and custom headers to req
+func (b *ParseRequestBuilder) setHeaders(req *http.Request) {
+	userAgent := b.client.userAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	for key, values := range b.client.header {
+		if !reservedHeaders[http.CanonicalHeaderKey(key)] {
+			req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+	for key, values := range b.req.Header {
+		if !reservedHeaders[http.CanonicalHeaderKey(key)] {
+			req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// logger returns the client logger, annotated with the request tags
+func (b *ParseRequestBuilder) logger() *slog.Logger {
+	if len(b.req.Tags) == 0 {
+		return b.client.logger
+	}
+	return b.client.logger.With(tagsAttr(b.req.Tags))
+}
+
+// tagsAttr groups tags under "tags" in sorted order
+func tagsAttr(tags map[string]string) slog.Attr {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.String(k, tags[k]))
+	}
+	return slog.Group("tags", attrs...)
+}
@@ -0,0 +1,110 @@
+package landingai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestBuilder_Headers(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		opts  []ClientOption
+		build func(*ParseRequestBuilder)
+		want  map[string]string
+	}{
+		{
+			name: "default user agent",
+			want: map[string]string{"User-Agent": DefaultUserAgent()},
+		},
+		{
+			name: "client headers",
+			opts: []ClientOption{WithUserAgent("billing/1.2"), WithHeader("X-Team", "billing")},
+			want: map[string]string{"User-Agent": "billing/1.2", "X-Team": "billing"},
+		},
+		{
+			name: "request overrides client",
+			opts: []ClientOption{WithUserAgent("billing/1.2"), WithHeader("X-Team", "billing")},
+			build: func(b *ParseRequestBuilder) {
+				b.WithUserAgent("nightly-job").WithHeader("X-Team", "search")
+			},
+			want: map[string]string{"User-Agent": "nightly-job", "X-Team": "search"},
+		},
+		{
+			name: "reserved headers ignored",
+			opts: []ClientOption{WithHeader("Authorization", "Bearer other")},
+			build: func(b *ParseRequestBuilder) {
+				b.WithHeader("Content-Type", "text/plain")
+			},
+			want: map[string]string{"Authorization": "Bearer test-api-key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-api-key", append([]ClientOption{WithBaseURL(server.URL)}, tt.opts...)...)
+			b := client.Parse(context.Background()).WithFileData([]byte("doc"), "doc.pdf")
+			if tt.build != nil {
+				tt.build(b)
+			}
+			if _, err := b.Do(); err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			for k, v := range tt.want {
+				if got.Get(k) != v {
+					t.Errorf("%s = %q, want %q", k, got.Get(k), v)
+				}
+			}
+			if !strings.HasPrefix(got.Get("Content-Type"), "multipart/form-data") {
+				t.Errorf("Content-Type = %q", got.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestParseRequestBuilder_WithTags(t *testing.T) {
+	status := StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"markdown":"ok","detail":"boom"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithLogger(logger))
+	tags := map[string]string{"customer": "acme", "pipeline": "invoices"}
+
+	resp, err := client.Parse(context.Background()).
+		WithFileData([]byte("doc"), "doc.pdf").
+		WithTags(map[string]string{"customer": "acme"}).
+		WithTags(map[string]string{"pipeline": "invoices"}).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if info := resp.HTTP(); info.Tags["customer"] != "acme" || info.Tags["pipeline"] != "invoices" {
+		t.Errorf("ResponseInfo.Tags = %v", info.Tags)
+	}
+	if !strings.Contains(logs.String(), "tags.customer=acme tags.pipeline=invoices") {
+		t.Errorf("logs = %q, want tags", logs.String())
+	}
+
+	status = StatusInternalServerError
+	_, err = client.Parse(context.Background()).WithFileData([]byte("doc"), "doc.pdf").WithTags(tags).Do()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Tags["customer"] != "acme" {
+		t.Errorf("Do() error = %v, want APIError with tags", err)
+	}
+}
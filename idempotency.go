@@ -0,0 +1,207 @@
+package landingai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a parse request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey sets the idempotency key sent with the request.
+// Concurrent requests on the same client with the same key and document share
+// one HTTP call; reusing an in-flight key for a different document or options
+// fails with ErrIdempotencyKeyReused.
+func (b *ParseRequestBuilder) WithIdempotencyKey(key string) *ParseRequestBuilder {
+	b.req.IdempotencyKey = key
+	return b
+}
+
+// WithAutoIdempotencyKey derives the idempotency key from a SHA-256 hash of the
+// document content and the request options. Document URLs and sources that
+// implement IdentifiableSource are keyed on their identity instead of being
+// downloaded twice.
+func (b *ParseRequestBuilder) WithAutoIdempotencyKey() *ParseRequestBuilder {
+	b.req.AutoIdempotencyKey = true
+	return b
+}
+
+// WithAutoIdempotencyKeys derives an idempotency key for every request that does
+// not set one explicitly, as WithAutoIdempotencyKey does
+func WithAutoIdempotencyKeys() ClientOption {
+	return func(c *Client) {
+		c.autoIdempotency = true
+	}
+}
+
+// IdentifiableSource is implemented by sources that can identify their
+// document without reading it, such as remote downloads. Auto idempotency keys
+// and in-flight deduplication use Identity instead of hashing the content, so
+// the document is only fetched once, for the upload.
+type IdentifiableSource interface {
+	DocumentSource
+	// Identity returns a stable identifier of the document, e.g. its URL
+	Identity() string
+}
+
+// ErrIdempotencyKeyReused is returned when a request reuses the idempotency key
+// of an in-flight request for a different document or options
+var ErrIdempotencyKeyReused = errors.New("landingai: idempotency key reused for a different request")
+
+// resolveIdempotencyKey returns the explicit key, a derived key, or "" if none applies
+func (b *ParseRequestBuilder) resolveIdempotencyKey() (string, error) {
+	if b.req.IdempotencyKey != "" {
+		return b.req.IdempotencyKey, nil
+	}
+	if !b.req.AutoIdempotencyKey && !b.client.autoIdempotency {
+		return "", nil
+	}
+
+	h := sha256.New()
+	src, _ := b.req.source(b.client)
+	switch src := src.(type) {
+	case nil:
+		fmt.Fprintf(h, "url:%s\n", *b.req.DocumentURL)
+	case IdentifiableSource:
+		fmt.Fprintf(h, "source:%s\n", src.Identity())
+	case *readerSource:
+		return "", fmt.Errorf("cannot derive an idempotency key from a single-use reader source")
+	default:
+		doc, _, err := src.Open(b.ctx)
+		if err != nil {
+			return "", err
+		}
+		defer doc.Close()
+		fmt.Fprint(h, "file\n")
+		if _, err := io.Copy(h, doc); err != nil {
+			return "", fmt.Errorf("failed to read document: %w", err)
+		}
+	}
+	b.req.ParseOptions.hashInto(h)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// payloadID identifies the document and options of a request without reading
+// the document from disk or the network. It is empty for sources that cannot
+// be identified, which are never deduplicated.
+func (r ParseRequest) payloadID(c *Client) string {
+	h := sha256.New()
+	src, _ := r.source(c)
+	switch src := src.(type) {
+	case nil:
+		fmt.Fprintf(h, "url:%s\n", *r.DocumentURL)
+	case IdentifiableSource:
+		fmt.Fprintf(h, "source:%s\n", src.Identity())
+	case BytesSource:
+		fmt.Fprint(h, "file\n")
+		h.Write(src.Data)
+	case FileSource:
+		fmt.Fprintf(h, "path:%s\n", src)
+	default:
+		return ""
+	}
+	r.ParseOptions.hashInto(h)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashInto writes the options that affect the parse result to h
+func (o ParseOptions) hashInto(h io.Writer) {
+	if o.Model != nil {
+		fmt.Fprintf(h, "\nmodel:%s", *o.Model)
+	}
+	if o.Split != nil {
+		fmt.Fprintf(h, "\nsplit:%s", *o.Split)
+	}
+}
+
+// flightGroup collapses concurrent calls with the same key into one execution
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	payload string
+	done    chan struct{}
+	resp    *ParseResponse
+	err     error
+
+	// waiters counts callers still waiting; the call is cancelled when all leave
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do runs fn once for all concurrent callers with the same key and payload,
+// and returns its result to each of them. fn runs on a context detached from
+// any single caller's cancellation, which is cancelled only once every caller
+// has given up. Each caller waits until the result is ready or its own ctx is
+// done, and callers that did not start the call receive a copy of the response.
+// A caller whose payload differs from the in-flight call's gets ErrIdempotencyKeyReused.
+func (g *flightGroup) do(ctx context.Context, key, payload string, fn func(ctx context.Context) (*ParseResponse, error)) (*ParseResponse, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, shared := g.calls[key]
+	if shared {
+		if payload == "" || call.payload != payload {
+			g.mu.Unlock()
+			return nil, fmt.Errorf("%w: %q is in use by another in-flight request", ErrIdempotencyKeyReused, key)
+		}
+		call.waiters++
+		g.mu.Unlock()
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{payload: payload, done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+		g.mu.Unlock()
+		go g.run(key, call, callCtx, fn)
+	}
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody is waiting any more: stop the call and let the next caller start afresh
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	if !shared || call.resp == nil {
+		return call.resp, call.err
+	}
+	resp, err := call.resp.clone()
+	if err != nil {
+		return nil, err
+	}
+	return resp, call.err
+}
+
+// run executes fn and publishes its result, turning a panic into an error
+func (g *flightGroup) run(key string, call *flightCall, ctx context.Context, fn func(context.Context) (*ParseResponse, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.resp, call.err = nil, fmt.Errorf("landingai: parse request panicked: %v", r)
+		}
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		call.cancel()
+		close(call.done)
+	}()
+	call.resp, call.err = fn(ctx)
+}
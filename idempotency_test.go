@@ -0,0 +1,202 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRequestBuilder_Deduplication(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	keys := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		keys <- r.Header.Get(IdempotencyKeyHeader)
+		<-release
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]*ParseResponse, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.Parse(context.Background()).
+				WithFileData([]byte("same document"), "doc.pdf").
+				WithModel(ModelDPT2Latest).
+				WithAutoIdempotencyKey().
+				Do()
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results[i] = resp
+		}(i)
+	}
+
+	key := <-keys
+	// Give the other callers time to join the in-flight request
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if len(key) != 64 {
+		t.Errorf("%s = %q, want SHA-256 hex", IdempotencyKeyHeader, key)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("server called %d times, want 1", n)
+	}
+	for i, r := range results {
+		if r == nil || r.Markdown != "ok" {
+			t.Fatalf("caller %d got %+v", i, r)
+		}
+		for j := range i {
+			if r == results[j] {
+				t.Errorf("callers %d and %d share response %p, want copies", j, i, r)
+			}
+		}
+	}
+}
+
+func TestParseRequestBuilder_DeduplicationKeyReused(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	go client.Parse(context.Background()).WithFileData([]byte("document A"), "a.pdf").WithIdempotencyKey("order-1").Do()
+	<-started
+
+	_, err := client.Parse(context.Background()).WithFileData([]byte("document B"), "b.pdf").WithIdempotencyKey("order-1").Do()
+	if !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Do() error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestParseRequestBuilder_DeduplicationCancellation(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	parse := func(ctx context.Context) (*ParseResponse, error) {
+		return client.Parse(ctx).WithFileData([]byte("doc"), "doc.pdf").WithAutoIdempotencyKey().Do()
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := parse(firstCtx)
+		firstErr <- err
+	}()
+	<-started
+
+	// A waiter gives up on its own deadline without waiting for the call
+	waiterCtx, cancelWaiter := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelWaiter()
+	if _, err := parse(waiterCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter error = %v, want DeadlineExceeded", err)
+	}
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := parse(context.Background())
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// The first caller leaving must not fail the remaining caller
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller error = %v, want Canceled", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("remaining caller error = %v", err)
+	}
+}
+
+func TestFlightGroup_Panic(t *testing.T) {
+	var g flightGroup
+	_, err := g.do(context.Background(), "key", "payload", func(context.Context) (*ParseResponse, error) {
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("do() error = %v, want the panic as an error", err)
+	}
+}
+
+func TestParseRequestBuilder_AutoKeyRemoteSourceFetchedOnce(t *testing.T) {
+	var downloads atomic.Int32
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.Write([]byte("%PDF remote"))
+	}))
+	defer origin.Close()
+
+	var name, content string
+	server := uploadServer(t, &name, &content)
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	_, err := client.Parse(context.Background()).
+		WithSource(HTTPSource{URL: origin.URL + "/doc.pdf"}).
+		WithAutoIdempotencyKey().
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if n := downloads.Load(); n != 1 || content != "%PDF remote" {
+		t.Errorf("downloaded %d times, uploaded %q; want one download", n, content)
+	}
+}
+
+func TestParseRequestBuilder_ResolveIdempotencyKey(t *testing.T) {
+	client := NewClient("test-api-key")
+	key := func(b *ParseRequestBuilder) string {
+		k, err := b.resolveIdempotencyKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	base := key(client.Parse(context.Background()).WithFileData([]byte("a"), "a.pdf").WithAutoIdempotencyKey())
+	same := key(client.Parse(context.Background()).WithFileData([]byte("a"), "renamed.pdf").WithAutoIdempotencyKey())
+	split := key(client.Parse(context.Background()).WithFileData([]byte("a"), "a.pdf").WithPageSplit().WithAutoIdempotencyKey())
+
+	if base != same {
+		t.Error("auto key depends on the file name")
+	}
+	if base == split {
+		t.Error("auto key ignores request options")
+	}
+	if got := key(client.Parse(context.Background()).WithFileData([]byte("a"), "a.pdf")); got != "" {
+		t.Errorf("key without idempotency = %q, want empty", got)
+	}
+	if got := key(client.Parse(context.Background()).WithIdempotencyKey("mine")); got != "mine" {
+		t.Errorf("explicit key = %q", got)
+	}
+}
@@ -0,0 +1,262 @@
+// Package markdown converts the markdown dialect produced by the Landing AI
+// parse API into plain text and HTML, and extracts tables from it.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockList
+	blockTable
+	blockFigure
+)
+
+type block struct {
+	kind    blockKind
+	level   int
+	ordered bool
+	lines   []string
+	table   Table
+}
+
+var (
+	anchorRe   = regexp.MustCompile(`(?is)<a\s+id=['"][^'"]*['"]\s*>\s*</a>`)
+	figureRe   = regexp.MustCompile(`(?s)<::(.*?)::>`)
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe  = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	imageRe    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)[^)]*\)`)
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]*)[^)]*\)`)
+	boldRe     = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	italicRe   = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:.*?\S)?)[*_]([^\w*]|$)`)
+	codeRe     = regexp.MustCompile("`([^`]+)`")
+	safeLinkRe = regexp.MustCompile(`(?i)^(https?:|mailto:|#|/)`)
+)
+
+// Heading returns the level (1-6) and text of a leading markdown heading.
+// The level is 0 if the first non-empty line is not a heading.
+func Heading(md string) (int, string) {
+	for _, line := range strings.Split(Clean(md), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			return len(m[1]), InlineText(StripTags(m[2]))
+		}
+		return 0, InlineText(StripTags(line))
+	}
+	return 0, ""
+}
+
+// Clean removes chunk anchors that carry no content
+func Clean(md string) string {
+	return strings.TrimSpace(anchorRe.ReplaceAllString(md, ""))
+}
+
+// parseBlocks splits markdown into block-level elements
+func parseBlocks(md string) []block {
+	md = Clean(md)
+
+	var blocks []block
+	last := 0
+	for _, loc := range htmlTableRe.FindAllStringSubmatchIndex(md, -1) {
+		blocks = append(blocks, parseMarkdownBlocks(md[last:loc[0]])...)
+		blocks = append(blocks, block{kind: blockTable, table: parseHTMLTable(md[loc[2]:loc[3]])})
+		last = loc[1]
+	}
+	return append(blocks, parseMarkdownBlocks(md[last:])...)
+}
+
+func parseMarkdownBlocks(md string) []block {
+	var blocks []block
+	var cur *block
+
+	flush := func() {
+		if cur != nil {
+			blocks = append(blocks, *cur)
+			cur = nil
+		}
+	}
+
+	lines := strings.Split(md, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case figureRe.MatchString(trimmed):
+			flush()
+			for _, m := range figureRe.FindAllStringSubmatch(trimmed, -1) {
+				blocks = append(blocks, block{kind: blockFigure, lines: []string{strings.TrimSpace(m[1])}})
+			}
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), lines: []string{m[2]}})
+		case isPipeRow(trimmed) && i+1 < len(lines) && pipeSepRe.MatchString(strings.TrimSpace(lines[i+1])):
+			flush()
+			table := Table{Rows: [][]Cell{pipeCells(trimmed, true)}}
+			i += 2
+			for ; i < len(lines) && isPipeRow(lines[i]); i++ {
+				table.Rows = append(table.Rows, pipeCells(lines[i], false))
+			}
+			i--
+			blocks = append(blocks, block{kind: blockTable, table: table})
+		case bulletRe.MatchString(line) || orderedRe.MatchString(line):
+			ordered := !bulletRe.MatchString(line)
+			if cur == nil || cur.kind != blockList || cur.ordered != ordered {
+				flush()
+				cur = &block{kind: blockList, ordered: ordered}
+			}
+			if ordered {
+				cur.lines = append(cur.lines, orderedRe.FindStringSubmatch(line)[1])
+			} else {
+				cur.lines = append(cur.lines, bulletRe.FindStringSubmatch(line)[1])
+			}
+		default:
+			if cur != nil && cur.kind == blockList {
+				// Continuation of the previous list item
+				cur.lines[len(cur.lines)-1] += " " + trimmed
+				continue
+			}
+			if cur == nil {
+				cur = &block{kind: blockParagraph}
+			}
+			cur.lines = append(cur.lines, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// InlineText removes inline markdown markup, keeping only the visible text
+func InlineText(s string) string {
+	s = imageRe.ReplaceAllString(s, "$1")
+	s = linkRe.ReplaceAllString(s, "$1")
+	s = codeRe.ReplaceAllString(s, "$1")
+	s = boldRe.ReplaceAllString(s, "$2")
+	s = italicRe.ReplaceAllString(s, "$1$2$3")
+	return s
+}
+
+// ToText converts chunk markdown to plain text.
+// Table rows become tab-separated lines and figure descriptions are kept as text.
+func ToText(md string) string {
+	var parts []string
+	for _, b := range parseBlocks(md) {
+		switch b.kind {
+		case blockTable:
+			var rows []string
+			for _, row := range b.table.Rows {
+				cells := make([]string, len(row))
+				for i, c := range row {
+					cells[i] = c.Text
+				}
+				rows = append(rows, strings.Join(cells, "\t"))
+			}
+			parts = append(parts, strings.Join(rows, "\n"))
+		case blockList:
+			items := make([]string, len(b.lines))
+			for i, l := range b.lines {
+				items[i] = InlineText(StripTags(l))
+			}
+			parts = append(parts, strings.Join(items, "\n"))
+		default:
+			text := InlineText(StripTags(strings.Join(b.lines, "\n")))
+			if text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ToHTML converts chunk markdown to HTML.
+// Raw HTML in the input is never passed through; tables are rebuilt from their cells.
+func ToHTML(md string) string {
+	var sb strings.Builder
+	for _, b := range parseBlocks(md) {
+		switch b.kind {
+		case blockHeading:
+			fmt.Fprintf(&sb, "<h%d>%s</h%d>\n", b.level, inlineHTML(b.lines[0]), b.level)
+		case blockList:
+			tag := "ul"
+			if b.ordered {
+				tag = "ol"
+			}
+			fmt.Fprintf(&sb, "<%s>\n", tag)
+			for _, item := range b.lines {
+				fmt.Fprintf(&sb, "<li>%s</li>\n", inlineHTML(item))
+			}
+			fmt.Fprintf(&sb, "</%s>\n", tag)
+		case blockTable:
+			writeTableHTML(&sb, b.table)
+		case blockFigure:
+			fmt.Fprintf(&sb, "<figure><figcaption>%s</figcaption></figure>\n", html.EscapeString(b.lines[0]))
+		default:
+			lines := make([]string, len(b.lines))
+			for i, l := range b.lines {
+				lines[i] = inlineHTML(l)
+			}
+			fmt.Fprintf(&sb, "<p>%s</p>\n", strings.Join(lines, "<br>\n"))
+		}
+	}
+	return sb.String()
+}
+
+func writeTableHTML(sb *strings.Builder, table Table) {
+	sb.WriteString("<table>\n")
+	for _, row := range table.Rows {
+		sb.WriteString("<tr>")
+		for _, c := range row {
+			tag := "td"
+			if c.Header {
+				tag = "th"
+			}
+			sb.WriteString("<" + tag)
+			if c.ColSpan > 1 {
+				fmt.Fprintf(sb, ` colspan="%d"`, c.ColSpan)
+			}
+			if c.RowSpan > 1 {
+				fmt.Fprintf(sb, ` rowspan="%d"`, c.RowSpan)
+			}
+			fmt.Fprintf(sb, ">%s</%s>", html.EscapeString(c.Text), tag)
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+}
+
+// inlineHTML escapes text and converts inline markdown markup to HTML
+func inlineHTML(s string) string {
+	s = html.EscapeString(StripTags(s))
+	s = imageRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := imageRe.FindStringSubmatch(m)
+		if !safeLinkRe.MatchString(sub[2]) {
+			return sub[1]
+		}
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, sub[2], sub[1])
+	})
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkRe.FindStringSubmatch(m)
+		if !safeLinkRe.MatchString(sub[2]) {
+			return sub[1]
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, sub[2], sub[1])
+	})
+	s = codeRe.ReplaceAllString(s, "<code>$1</code>")
+	s = boldRe.ReplaceAllString(s, "<strong>$2</strong>")
+	s = italicRe.ReplaceAllString(s, "$1<em>$2</em>$3")
+	return s
+}
@@ -0,0 +1,149 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeading(t *testing.T) {
+	tests := []struct {
+		md        string
+		wantLevel int
+		wantText  string
+	}{
+		{"# Title", 1, "Title"},
+		{"### Sub ###", 3, "Sub"},
+		{"<a id='c1'></a>\n\n## **Bold** heading", 2, "Bold heading"},
+		{"Plain *text*", 0, "Plain text"},
+		{"#NoSpace", 0, "#NoSpace"},
+		{"\n\nBody\n# Later", 0, "Body"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		level, text := Heading(tt.md)
+		if level != tt.wantLevel || text != tt.wantText {
+			t.Errorf("Heading(%q) = %d, %q, want %d, %q", tt.md, level, text, tt.wantLevel, tt.wantText)
+		}
+	}
+}
+
+func TestToText(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{name: "inline markup", md: "See [docs](https://example.com) **now** and `code`", want: "See docs now and code"},
+		{name: "image and emphasis", md: "![logo](https://example.com/l.png) _it_ snake_case_name", want: "logo it snake_case_name"},
+		{name: "paragraphs", md: "line one\nline two\n\npara two", want: "line one\nline two\n\npara two"},
+		{name: "bullet list with continuation", md: "- a\n- b\n  continued", want: "a\nb continued"},
+		{name: "ordered list", md: "1. one\n2) two", want: "one\ntwo"},
+		{name: "pipe table", md: "| a | b |\n|---|---|\n| 1 | 2 |", want: "a\tb\n1\t2"},
+		{name: "html table", md: "<table><tr><th>a</th><td>b &amp; c</td></tr></table>", want: "a\tb & c"},
+		{name: "figure", md: "<::A chart::>", want: "A chart"},
+		{name: "entities and breaks", md: "Fish &amp; chips<br>x", want: "Fish & chips\nx"},
+		{name: "pipe rows without separator", md: "| a | b |\n| 1 | 2 |", want: "| a | b |\n| 1 | 2 |"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToText(tt.md); got != tt.want {
+				t.Errorf("ToText(%q) = %q, want %q", tt.md, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{name: "heading", md: "## T", want: "<h2>T</h2>\n"},
+		{name: "emphasis", md: "**b** and *i*", want: "<p><strong>b</strong> and <em>i</em></p>\n"},
+		{name: "lists", md: "- a\n1. b", want: "<ul>\n<li>a</li>\n</ul>\n<ol>\n<li>b</li>\n</ol>\n"},
+		{
+			name: "safe links",
+			md:   "[a](https://a.example) [m](mailto:a@b.example) [r](/p) [f](#s)",
+			want: `<p><a href="https://a.example">a</a> <a href="mailto:a@b.example">m</a> <a href="/p">r</a> <a href="#s">f</a></p>` + "\n",
+		},
+		{name: "raw html", md: "<script>alert(1)</script>hi", want: "<p>alert(1)hi</p>\n"},
+		{name: "escaped entities", md: "a <b>&lt;</b> c", want: "<p>a &lt; c</p>\n"},
+		{name: "quote in link target", md: `[x](https://a"onmouseover=1)`, want: `<p><a href="https://a&#34;onmouseover=1">x</a></p>` + "\n"},
+		{
+			name: "html table",
+			md:   `<table><tr><th colspan="2">H</th></tr><tr><td>a &amp; b</td><td><b>c</b></td></tr></table>`,
+			want: "<table>\n<tr><th colspan=\"2\">H</th></tr>\n<tr><td>a &amp; b</td><td>c</td></tr>\n</table>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.md); got != tt.want {
+				t.Errorf("ToHTML(%q) = %q, want %q", tt.md, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToHTML_UnsafeLinks(t *testing.T) {
+	for _, md := range []string{
+		"[x](javascript:alert(1))",
+		"[x](JavaScript:alert)",
+		"[x](vbscript:msgbox)",
+		"[x](data:text/html;base64,PHNjcmlwdD4=)",
+		"![x](javascript:alert)",
+		"![x](data:image/svg+xml;base64,PHN2Zz4=)",
+	} {
+		got := ToHTML(md)
+		if strings.Contains(got, "href") || strings.Contains(got, "src") || !strings.Contains(got, "x") {
+			t.Errorf("ToHTML(%q) = %q, want the text without a link", md, got)
+		}
+	}
+}
+
+func TestParseTables(t *testing.T) {
+	cell := func(text string, header bool) Cell {
+		return Cell{Text: text, Header: header, ColSpan: 1, RowSpan: 1}
+	}
+	tests := []struct {
+		name string
+		md   string
+		want []Table
+	}{
+		{
+			name: "pipe table with alignment and a short row",
+			md:   "| a | **b** |\n|:---|---:|\n| 1 | 2 |\n| 3 |",
+			want: []Table{{Rows: [][]Cell{
+				{cell("a", true), cell("b", true)},
+				{cell("1", false), cell("2", false)},
+				{cell("3", false)},
+			}}},
+		},
+		{
+			name: "html before pipe tables, with invalid spans",
+			md:   "| p |\n| --- |\n| 1 |\n<table><tr><td colspan=0 rowspan='3'>x</td><td colspan=\"abc\">y</td></tr></table>",
+			want: []Table{
+				{Rows: [][]Cell{{{Text: "x", ColSpan: 1, RowSpan: 3}, cell("y", false)}}},
+				{Rows: [][]Cell{{cell("p", true)}, {cell("1", false)}}},
+			},
+		},
+		{name: "no separator row", md: "| a | b |\n| 1 | 2 |"},
+		{name: "separator without leading pipe row", md: "text | not | table\n---|---"},
+		{name: "single pipe row", md: "| only |"},
+		{name: "unclosed html table", md: "<table><tr><td>a</td></tr>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTables(tt.md); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTables() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	if got := StripTags(" <p>a<br/>b &amp; <i>c</i></p> "); got != "a\nb & c" {
+		t.Errorf("StripTags() = %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Cell is a single table cell
+type Cell struct {
+	Text    string
+	Header  bool
+	ColSpan int
+	RowSpan int
+}
+
+// Table is a table extracted from chunk markdown, either an HTML table or a pipe table
+type Table struct {
+	Rows [][]Cell
+}
+
+var (
+	htmlTableRe = regexp.MustCompile(`(?is)<table\b[^>]*>(.*?)</table>`)
+	htmlRowRe   = regexp.MustCompile(`(?is)<tr\b[^>]*>(.*?)</tr>`)
+	htmlCellRe  = regexp.MustCompile(`(?is)<(td|th)\b([^>]*)>(.*?)</(?:td|th)>`)
+	colSpanRe   = regexp.MustCompile(`(?i)colspan\s*=\s*["']?(\d+)`)
+	rowSpanRe   = regexp.MustCompile(`(?i)rowspan\s*=\s*["']?(\d+)`)
+	pipeSepRe   = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+)
+
+// ParseTables extracts every table in the given markdown.
+// HTML tables are returned first, followed by pipe tables.
+func ParseTables(md string) []Table {
+	var tables []Table
+	for _, m := range htmlTableRe.FindAllStringSubmatch(md, -1) {
+		tables = append(tables, parseHTMLTable(m[1]))
+	}
+	rest := htmlTableRe.ReplaceAllString(md, "")
+	lines := strings.Split(rest, "\n")
+	for i := 0; i < len(lines); i++ {
+		if !isPipeRow(lines[i]) || i+1 >= len(lines) || !pipeSepRe.MatchString(strings.TrimSpace(lines[i+1])) {
+			continue
+		}
+		table := Table{Rows: [][]Cell{pipeCells(lines[i], true)}}
+		i += 2
+		for ; i < len(lines) && isPipeRow(lines[i]); i++ {
+			table.Rows = append(table.Rows, pipeCells(lines[i], false))
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+func parseHTMLTable(body string) Table {
+	var table Table
+	for _, row := range htmlRowRe.FindAllStringSubmatch(body, -1) {
+		var cells []Cell
+		for _, c := range htmlCellRe.FindAllStringSubmatch(row[1], -1) {
+			cells = append(cells, Cell{
+				Text:    InlineText(StripTags(c[3])),
+				Header:  strings.EqualFold(c[1], "th"),
+				ColSpan: spanAttr(colSpanRe, c[2]),
+				RowSpan: spanAttr(rowSpanRe, c[2]),
+			})
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+func spanAttr(re *regexp.Regexp, attrs string) int {
+	m := re.FindStringSubmatch(attrs)
+	if m == nil {
+		return 1
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+func isPipeRow(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "|") && strings.Count(line, "|") >= 2
+}
+
+func pipeCells(line string, header bool) []Cell {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	parts := strings.Split(line, "|")
+	cells := make([]Cell, 0, len(parts))
+	for _, p := range parts {
+		cells = append(cells, Cell{Text: InlineText(strings.TrimSpace(p)), Header: header, ColSpan: 1, RowSpan: 1})
+	}
+	return cells
+}
+
+var (
+	tagRe = regexp.MustCompile(`(?s)<[^>]*>`)
+	brRe  = regexp.MustCompile(`(?i)<br\s*/?>`)
+)
+
+// StripTags removes HTML tags and unescapes entities
+func StripTags(s string) string {
+	s = brRe.ReplaceAllString(s, "\n")
+	return strings.TrimSpace(html.UnescapeString(tagRe.ReplaceAllString(s, "")))
+}
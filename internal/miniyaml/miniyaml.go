@@ -0,0 +1,173 @@
+// Package miniyaml decodes the flat subset of YAML used by option files:
+// top-level "key: value" pairs whose values are scalars, flow lists
+// ("[a, b]") or block lists ("- a" lines). Nested mappings, anchors and
+// multi-line strings are not supported.
+package miniyaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes a document into a map of scalars (string, float64, bool,
+// nil) and lists of scalars
+func Unmarshal(data []byte) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	var listKey string // key of the block list being read, if any
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := strings.TrimRight(stripComment(raw), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "-"); ok && (item == "" || item[0] == ' ') {
+			if listKey == "" {
+				return nil, fmt.Errorf("line %d: list item without a key", lineNo)
+			}
+			value, err := scalar(strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			out[listKey] = append(out[listKey].([]interface{}), value)
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, fmt.Errorf("line %d: nested mappings are not supported", lineNo)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if _, dup := out[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", lineNo, key)
+		}
+
+		listKey = ""
+		switch {
+		case value == "":
+			// A block list may follow; an empty value otherwise reads as null
+			out[key] = []interface{}{}
+			listKey = key
+		case strings.HasPrefix(value, "["):
+			list, err := flowList(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			out[key] = list
+		case strings.HasPrefix(value, "{"):
+			return nil, fmt.Errorf("line %d: nested mappings are not supported", lineNo)
+		default:
+			v, err := scalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			out[key] = v
+		}
+	}
+
+	// Keys with no value and no list items are null, as in YAML
+	for key, value := range out {
+		if list, ok := value.([]interface{}); ok && len(list) == 0 {
+			out[key] = nil
+		}
+	}
+	return out, nil
+}
+
+// flowList decodes "[a, b, c]"
+func flowList(s string) ([]interface{}, error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(s, "["), "]")
+	if !ok {
+		return nil, fmt.Errorf("unterminated list %q", s)
+	}
+	list := []interface{}{}
+	if strings.TrimSpace(inner) == "" {
+		return list, nil
+	}
+	for _, item := range splitFlow(inner) {
+		v, err := scalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// splitFlow splits flow list items on commas outside quotes
+func splitFlow(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// scalar decodes a plain or quoted scalar
+func scalar(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		inner, ok := strings.CutSuffix(s[1:], "'")
+		if !ok {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return strings.ReplaceAll(inner, "''", "'"), nil
+	case strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{"):
+		return nil, fmt.Errorf("nested collections are not supported")
+	}
+
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// stripComment removes a trailing "# comment" outside quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,55 @@
+package miniyaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	doc := `---
+# Invoice profile
+model: dpt-2-20250919   # pinned snapshot
+split: "page"
+auto_idempotency_key: true
+require_chunk_types: [table, 'key # value']
+require_grounding_types:
+  - chunkTable
+  - "tableCell"
+empty:
+`
+	got, err := Unmarshal([]byte(doc))
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"model":                   "dpt-2-20250919",
+		"split":                   "page",
+		"auto_idempotency_key":    true,
+		"require_chunk_types":     []interface{}{"table", "key # value"},
+		"require_grounding_types": []interface{}{"chunkTable", "tableCell"},
+		"empty":                   nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %#v, want %#v", got, want)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{doc: "options:\n  model: dpt-2-latest\n", want: "line 2: nested mappings"},
+		{doc: "- table\n", want: "line 1: list item without a key"},
+		{doc: "model\n", want: `line 1: expected "key: value"`},
+		{doc: "model: a\nmodel: b\n", want: `line 2: duplicate key "model"`},
+		{doc: "types: [a, b\n", want: "line 1: unterminated list"},
+	}
+	for _, tt := range tests {
+		_, err := Unmarshal([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Unmarshal(%q) error = %v, want %q", tt.doc, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package strdist
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("invoice number", "invoice number"); got != 1 {
+		t.Errorf("Similarity(identical) = %v, want 1", got)
+	}
+	if got := Similarity("abcd", "abcf"); got != 0.75 {
+		t.Errorf("Similarity(abcd, abcf) = %v, want 0.75", got)
+	}
+}
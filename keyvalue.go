@@ -0,0 +1,254 @@
+package landingai
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/youssefsiam38/landingai/internal/markdown"
+	"github.com/youssefsiam38/landingai/internal/strdist"
+)
+
+// DefaultKeyMatchThreshold is the minimum key similarity accepted by KeyValues.Lookup
+const DefaultKeyMatchThreshold = 0.8
+
+// KeyValue is a key-value pair extracted from a key-value or form chunk
+type KeyValue struct {
+	Key     string            `json:"key"`
+	Value   string            `json:"value"`
+	ChunkID string            `json:"chunk_id"`
+	Box     ParseGroundingBox `json:"box"`
+	Page    int               `json:"page"`
+}
+
+// NormalizedKey returns the key normalized with NormalizeKey
+func (kv KeyValue) NormalizedKey() string {
+	return NormalizeKey(kv.Key)
+}
+
+// KeyValues is a list of extracted key-value pairs in document order
+type KeyValues []KeyValue
+
+var kvLineRe = regexp.MustCompile(`^([^:]{1,80}?)\s*:\s*(.*)$`)
+
+// KeyValues extracts key-value pairs from chunkKeyValue and chunkForm chunks.
+// Pairs are read from "Key: Value" lines and two-column tables in the chunk
+// markdown. A trailing key without a value ("Name:") is paired with the nearest
+// value-only chunk to its right or below on the same page, or kept with an
+// empty value.
+func (r *ParseResponse) KeyValues() KeyValues {
+	var kvs KeyValues
+	var keys, values []kvFragment
+
+	for _, chunk := range r.Chunks {
+		switch r.GroundingTypeOf(chunk.ID) {
+		case GroundingTypeChunkKeyValue, GroundingTypeChunkForm:
+		default:
+			continue
+		}
+
+		pairs, danglingKey, loose := parseKeyValueMarkdown(chunk.Markdown)
+		for _, p := range pairs {
+			kvs = append(kvs, KeyValue{
+				Key:     p[0],
+				Value:   p[1],
+				ChunkID: chunk.ID,
+				Box:     chunk.Grounding.Box,
+				Page:    chunk.Grounding.Page,
+			})
+		}
+		switch {
+		case danglingKey != "":
+			keys = append(keys, kvFragment{text: danglingKey, chunk: chunk, index: len(kvs)})
+		case len(pairs) == 0 && loose != "":
+			values = append(values, kvFragment{text: loose, chunk: chunk})
+		}
+	}
+
+	// Pair trailing keys with value-only chunks using their geometry, keeping
+	// unpaired keys with an empty value. Insert from the back so earlier
+	// insertion indexes stay valid.
+	paired := pairFragments(keys, values)
+	for i := len(keys) - 1; i >= 0; i-- {
+		k := keys[i]
+		kv := KeyValue{
+			Key:     k.text,
+			ChunkID: k.chunk.ID,
+			Box:     k.chunk.Grounding.Box,
+			Page:    k.chunk.Grounding.Page,
+		}
+		if v, ok := paired[i]; ok {
+			kv.Value = v.text
+			kv.Box = kv.Box.Union(v.chunk.Grounding.Box)
+		}
+		kvs = append(kvs[:k.index], append(KeyValues{kv}, kvs[k.index:]...)...)
+	}
+	return kvs
+}
+
+// kvFragment is a key or value that could not be paired within its own chunk
+type kvFragment struct {
+	text  string
+	chunk ParseChunk
+	index int
+}
+
+// parseKeyValueMarkdown returns the pairs found in chunk markdown, a trailing
+// key without a value, and the chunk text if no key was found at all
+func parseKeyValueMarkdown(md string) (pairs [][2]string, danglingKey, loose string) {
+	for _, table := range markdown.ParseTables(md) {
+		for _, row := range table.Rows {
+			if len(row) == 2 && row[0].Text != "" {
+				pairs = append(pairs, [2]string{strings.TrimSuffix(row[0].Text, ":"), row[1].Text})
+			}
+		}
+	}
+	if len(pairs) > 0 {
+		return pairs, "", ""
+	}
+
+	text := markdown.ToText(md)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m := kvLineRe.FindStringSubmatch(line)
+		if m != nil && strings.HasPrefix(m[2], "//") {
+			// A URL, not a "Key: Value" line
+			m = nil
+		}
+		if m == nil {
+			if danglingKey != "" {
+				// "Key:" followed by the value on the next line
+				pairs = append(pairs, [2]string{danglingKey, line})
+				danglingKey = ""
+			}
+			continue
+		}
+		if danglingKey != "" {
+			pairs = append(pairs, [2]string{danglingKey, ""})
+		}
+		if m[2] == "" {
+			danglingKey = m[1]
+			continue
+		}
+		danglingKey = ""
+		pairs = append(pairs, [2]string{m[1], m[2]})
+	}
+	if len(pairs) == 0 && danglingKey == "" {
+		loose = strings.TrimSpace(text)
+	}
+	return pairs, danglingKey, loose
+}
+
+// pairFragments matches each key with the closest unused value to its right on
+// the same line or below it in the same column, returning matches by key index
+func pairFragments(keys, values []kvFragment) map[int]kvFragment {
+	paired := make(map[int]kvFragment)
+	used := make([]bool, len(values))
+	for i, k := range keys {
+		best, bestDist := -1, math.Inf(1)
+		kb := k.chunk.Grounding.Box
+		for j, v := range values {
+			if used[j] || v.chunk.Grounding.Page != k.chunk.Grounding.Page {
+				continue
+			}
+			vb := v.chunk.Grounding.Box
+			var dist float64
+			switch {
+			case overlaps(kb.Top, kb.Bottom, vb.Top, vb.Bottom) && vb.Left >= kb.Left:
+				dist = vb.Left - kb.Right
+			case overlaps(kb.Left, kb.Right, vb.Left, vb.Right) && vb.Top >= kb.Top:
+				// Values below the key are less likely than values beside it
+				dist = 2 * (vb.Top - kb.Bottom)
+			default:
+				continue
+			}
+			dist = math.Abs(dist)
+			if dist < bestDist {
+				best, bestDist = j, dist
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+			paired[i] = values[best]
+		}
+	}
+	return paired
+}
+
+func overlaps(aStart, aEnd, bStart, bEnd float64) bool {
+	return aStart < bEnd && bStart < aEnd
+}
+
+// NormalizeKey lowercases a key, drops punctuation and collapses whitespace,
+// so "Invoice No.:" and "invoice  no" compare equal
+func NormalizeKey(key string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(key) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteRune(r)
+			space = false
+		case unicode.IsSpace(r) || r == '_' || r == '-' || r == '/':
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+// Get returns the first pair whose normalized key equals the normalized name
+func (kvs KeyValues) Get(name string) (KeyValue, bool) {
+	want := NormalizeKey(name)
+	for _, kv := range kvs {
+		if kv.NormalizedKey() == want {
+			return kv, true
+		}
+	}
+	return KeyValue{}, false
+}
+
+// Lookup returns the pair whose key best matches name, accepting fuzzy matches
+// with a similarity of at least DefaultKeyMatchThreshold
+func (kvs KeyValues) Lookup(name string) (KeyValue, bool) {
+	return kvs.LookupThreshold(name, DefaultKeyMatchThreshold)
+}
+
+// LookupThreshold returns the pair whose normalized key is most similar to the
+// normalized name, if the similarity (0 to 1) is at least threshold
+func (kvs KeyValues) LookupThreshold(name string, threshold float64) (KeyValue, bool) {
+	if kv, ok := kvs.Get(name); ok {
+		return kv, true
+	}
+
+	want := NormalizeKey(name)
+	var best KeyValue
+	bestScore := -1.0
+	for _, kv := range kvs {
+		if score := strdist.Similarity(kv.NormalizedKey(), want); score > bestScore {
+			best, bestScore = kv, score
+		}
+	}
+	if bestScore < threshold {
+		return KeyValue{}, false
+	}
+	return best, true
+}
+
+// Map returns the pairs as a map from normalized key to value.
+// When a key repeats, the first value wins.
+func (kvs KeyValues) Map() map[string]string {
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if _, ok := m[kv.NormalizedKey()]; !ok {
+			m[kv.NormalizedKey()] = kv.Value
+		}
+	}
+	return m
+}
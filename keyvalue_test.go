@@ -0,0 +1,78 @@
+package landingai
+
+import "testing"
+
+func TestParseResponse_KeyValues(t *testing.T) {
+	resp := &ParseResponse{
+		Chunks: []ParseChunk{
+			{ID: "kv1", Markdown: "**Invoice No.:** INV-001\nDate: 2025-01-02"},
+			{ID: "kv3", Markdown: "Name: John\nDue date:"},
+			{ID: "txt", Markdown: "Thank you: for your business"},
+			{ID: "kv2", Markdown: "| Customer | ACME Corp |\n|---|---|\n| Total | $42.00 |"},
+			{
+				ID:        "key",
+				Markdown:  "Signed by:",
+				Grounding: ParseGrounding{Page: 1, Box: ParseGroundingBox{Left: 0.1, Top: 0.5, Right: 0.3, Bottom: 0.55}},
+			},
+			{
+				ID:        "far",
+				Markdown:  "Jane Smith",
+				Grounding: ParseGrounding{Page: 1, Box: ParseGroundingBox{Left: 0.7, Top: 0.5, Right: 0.9, Bottom: 0.55}},
+			},
+			{
+				ID:        "near",
+				Markdown:  "John Doe",
+				Grounding: ParseGrounding{Page: 1, Box: ParseGroundingBox{Left: 0.35, Top: 0.5, Right: 0.5, Bottom: 0.55}},
+			},
+		},
+		Grounding: map[string]ParseResponseGrounding{
+			"kv1":  {Type: GroundingTypeChunkKeyValue},
+			"kv3":  {Type: GroundingTypeChunkKeyValue},
+			"txt":  {Type: GroundingTypeChunkText},
+			"kv2":  {Type: GroundingTypeChunkForm},
+			"key":  {Type: GroundingTypeChunkForm},
+			"far":  {Type: GroundingTypeChunkForm},
+			"near": {Type: GroundingTypeChunkForm},
+		},
+	}
+
+	kvs := resp.KeyValues()
+
+	tests := []struct {
+		lookup    string
+		wantValue string
+		wantChunk string
+	}{
+		{lookup: "invoice no", wantValue: "INV-001", wantChunk: "kv1"},
+		{lookup: "DATE", wantValue: "2025-01-02", wantChunk: "kv1"},
+		{lookup: "name", wantValue: "John", wantChunk: "kv3"},
+		{lookup: "due date", wantValue: "", wantChunk: "kv3"},
+		{lookup: "Custmer", wantValue: "ACME Corp", wantChunk: "kv2"},
+		{lookup: "total", wantValue: "$42.00", wantChunk: "kv2"},
+		{lookup: "signed by", wantValue: "John Doe", wantChunk: "key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lookup, func(t *testing.T) {
+			kv, ok := kvs.Lookup(tt.lookup)
+			if !ok {
+				t.Fatalf("Lookup(%q) not found in %+v", tt.lookup, kvs)
+			}
+			if kv.Value != tt.wantValue || kv.ChunkID != tt.wantChunk {
+				t.Errorf("Lookup(%q) = %q from %s, want %q from %s", tt.lookup, kv.Value, kv.ChunkID, tt.wantValue, tt.wantChunk)
+			}
+		})
+	}
+
+	if _, ok := kvs.Lookup("thank you"); ok {
+		t.Error("Lookup() matched a pair from a plain text chunk")
+	}
+	if _, ok := kvs.Lookup("shipping address"); ok {
+		t.Error("Lookup() matched an unrelated key")
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	if got := NormalizeKey("  Invoice_No.: "); got != "invoice no" {
+		t.Errorf("NormalizeKey() = %q, want %q", got, "invoice no")
+	}
+}
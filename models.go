@@ -0,0 +1,200 @@
+package landingai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Model identifies a Landing AI parsing model or model snapshot
+type Model string
+
+const (
+	// ModelDPT2Latest always uses the newest DPT-2 snapshot (API default)
+	ModelDPT2Latest Model = "dpt-2-latest"
+	// ModelDPT220250919 is the September 19, 2025 DPT-2 snapshot
+	ModelDPT220250919 Model = "dpt-2-20250919"
+	// ModelDPT1Latest always uses the newest DPT-1 snapshot
+	ModelDPT1Latest Model = "dpt-1-latest"
+	// ModelDPT2MiniLatest always uses the newest DPT-2 mini snapshot
+	ModelDPT2MiniLatest Model = "DPT-2-mini-latest"
+)
+
+// ModelFamily represents a family of parsing models sharing the same capabilities
+type ModelFamily string
+
+const (
+	ModelFamilyDPT1     ModelFamily = "dpt-1"
+	ModelFamilyDPT2     ModelFamily = "dpt-2"
+	ModelFamilyDPT2Mini ModelFamily = "dpt-2-mini"
+)
+
+// modelSnapshotLayout is the date layout used in model snapshot identifiers
+const modelSnapshotLayout = "20060102"
+
+// ModelInfo describes a parsed model identifier
+type ModelInfo struct {
+	Model    Model
+	Family   ModelFamily
+	Latest   bool
+	Snapshot time.Time // Zero when Latest is true
+}
+
+// ParseModel parses a model identifier such as "dpt-2-latest" or "dpt-2-20250919".
+// Family names are matched case-insensitively, so "DPT-2-mini-latest" and
+// "dpt-2-mini-latest" are equivalent.
+func ParseModel(s string) (ModelInfo, error) {
+	lower := strings.ToLower(strings.TrimSpace(s))
+
+	// Longest prefix first so "dpt-2-mini" is not mistaken for "dpt-2"
+	families := []ModelFamily{ModelFamilyDPT2Mini, ModelFamilyDPT2, ModelFamilyDPT1}
+	for _, family := range families {
+		prefix := string(family) + "-"
+		if !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		suffix := strings.TrimPrefix(lower, prefix)
+		info := ModelInfo{Model: Model(s), Family: family}
+		if suffix == "latest" {
+			info.Latest = true
+			return info, nil
+		}
+		snapshot, err := time.Parse(modelSnapshotLayout, suffix)
+		if err != nil {
+			return ModelInfo{}, fmt.Errorf("invalid snapshot %q in model %q", suffix, s)
+		}
+		info.Snapshot = snapshot
+		return info, nil
+	}
+
+	return ModelInfo{}, fmt.Errorf("unknown model %q", s)
+}
+
+// Family returns the model family, or an empty string if the model is not recognized
+func (m Model) Family() ModelFamily {
+	info, err := ParseModel(string(m))
+	if err != nil {
+		return ""
+	}
+	return info.Family
+}
+
+// Snapshot returns the snapshot date of a dated model identifier.
+// The second return value is false for "-latest" or unrecognized models.
+func (m Model) Snapshot() (time.Time, bool) {
+	info, err := ParseModel(string(m))
+	if err != nil || info.Latest {
+		return time.Time{}, false
+	}
+	return info.Snapshot, true
+}
+
+// Capabilities returns the capabilities of the model's family.
+// The second return value is false if the model is not recognized.
+func (m Model) Capabilities() (ModelCapabilities, bool) {
+	caps, ok := modelCapabilities[m.Family()]
+	return caps, ok
+}
+
+// String returns the string representation of the model
+func (m Model) String() string {
+	return string(m)
+}
+
+// ModelCapabilities describes the features supported by a model family
+type ModelCapabilities struct {
+	ChunkTypes     []ChunkType
+	GroundingTypes []GroundingType
+	Split          bool
+}
+
+// SupportsChunkType returns true if the model can produce the given chunk type
+func (c ModelCapabilities) SupportsChunkType(t ChunkType) bool {
+	for _, ct := range c.ChunkTypes {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsGroundingType returns true if the model can produce the given grounding type
+func (c ModelCapabilities) SupportsGroundingType(t GroundingType) bool {
+	for _, gt := range c.GroundingTypes {
+		if gt == t {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	baseChunkTypes = []ChunkType{
+		ChunkTypeText,
+		ChunkTypeTable,
+		ChunkTypeMarginalia,
+		ChunkTypeFigure,
+	}
+	baseGroundingTypes = []GroundingType{
+		GroundingTypeChunkText,
+		GroundingTypeChunkTable,
+		GroundingTypeChunkFigure,
+		GroundingTypeChunkMarginalia,
+		GroundingTypeChunkTitle,
+		GroundingTypeChunkPageHeader,
+		GroundingTypeChunkPageFooter,
+		GroundingTypeChunkPageNumber,
+		GroundingTypeChunkForm,
+		GroundingTypeChunkKeyValue,
+	}
+)
+
+// modelCapabilities is the capability matrix for each model family.
+// Logos, cards, attestations, scan codes and table cell grounding are DPT-2 only.
+var modelCapabilities = map[ModelFamily]ModelCapabilities{
+	ModelFamilyDPT1: {
+		ChunkTypes:     baseChunkTypes,
+		GroundingTypes: baseGroundingTypes,
+		Split:          true,
+	},
+	ModelFamilyDPT2: {
+		ChunkTypes: append(append([]ChunkType{}, baseChunkTypes...),
+			ChunkTypeLogo,
+			ChunkTypeCard,
+			ChunkTypeAttestation,
+			ChunkTypeScanCode,
+		),
+		GroundingTypes: append(append([]GroundingType{}, baseGroundingTypes...),
+			GroundingTypeChunkLogo,
+			GroundingTypeChunkCard,
+			GroundingTypeChunkAttestation,
+			GroundingTypeChunkScanCode,
+			GroundingTypeTable,
+			GroundingTypeTableCell,
+		),
+		Split: true,
+	},
+	ModelFamilyDPT2Mini: {
+		ChunkTypes:     baseChunkTypes,
+		GroundingTypes: baseGroundingTypes,
+		Split:          true,
+	},
+}
+
+// CapabilitiesFor returns the capabilities of the given model family
+func CapabilitiesFor(family ModelFamily) (ModelCapabilities, bool) {
+	caps, ok := modelCapabilities[family]
+	return caps, ok
+}
+
+// CapabilityCheck controls how Do reacts when the chosen model lacks a required feature
+type CapabilityCheck int
+
+const (
+	// CapabilityCheckWarn logs a warning and sends the request anyway (default)
+	CapabilityCheckWarn CapabilityCheck = iota
+	// CapabilityCheckError fails the request with a *CapabilityError before sending it
+	CapabilityCheckError
+	// CapabilityCheckOff disables capability checks
+	CapabilityCheckOff
+)
@@ -0,0 +1,111 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseModel(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      string
+		wantFamily ModelFamily
+		wantLatest bool
+		wantDate   time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "DPT-2 latest",
+			model:      "dpt-2-latest",
+			wantFamily: ModelFamilyDPT2,
+			wantLatest: true,
+		},
+		{
+			name:       "DPT-2 snapshot",
+			model:      "dpt-2-20250919",
+			wantFamily: ModelFamilyDPT2,
+			wantDate:   time.Date(2025, 9, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "DPT-2 mini mixed case",
+			model:      "DPT-2-mini-20251003",
+			wantFamily: ModelFamilyDPT2Mini,
+			wantDate:   time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "DPT-1 latest",
+			model:      "dpt-1-latest",
+			wantFamily: ModelFamilyDPT1,
+			wantLatest: true,
+		},
+		{
+			name:    "invalid snapshot",
+			model:   "dpt-2-2025",
+			wantErr: true,
+		},
+		{
+			name:    "unknown family",
+			model:   "dpt-3-latest",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseModel(tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Family != tt.wantFamily {
+				t.Errorf("Family = %v, want %v", got.Family, tt.wantFamily)
+			}
+			if got.Latest != tt.wantLatest {
+				t.Errorf("Latest = %v, want %v", got.Latest, tt.wantLatest)
+			}
+			if !got.Snapshot.Equal(tt.wantDate) {
+				t.Errorf("Snapshot = %v, want %v", got.Snapshot, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestModel_Capabilities(t *testing.T) {
+	caps, ok := ModelDPT2Latest.Capabilities()
+	if !ok {
+		t.Fatal("Capabilities() not found for DPT-2")
+	}
+	if !caps.SupportsChunkType(ChunkTypeLogo) {
+		t.Error("DPT-2 should support logo chunks")
+	}
+
+	caps, ok = ModelDPT1Latest.Capabilities()
+	if !ok {
+		t.Fatal("Capabilities() not found for DPT-1")
+	}
+	if caps.SupportsChunkType(ChunkTypeLogo) {
+		t.Error("DPT-1 should not support logo chunks")
+	}
+}
+
+func TestParseRequestBuilder_CapabilityCheck(t *testing.T) {
+	client := NewClient("test-api-key", WithCapabilityCheck(CapabilityCheckError))
+
+	_, err := client.Parse(context.Background()).
+		WithFileData([]byte("data"), "doc.pdf").
+		WithModel(ModelDPT1Latest).
+		RequireChunkTypes(ChunkTypeScanCode).
+		Do()
+
+	var capErr *CapabilityError
+	if !errors.As(err, &capErr) {
+		t.Fatalf("Do() error = %v, want *CapabilityError", err)
+	}
+	if len(capErr.ChunkTypes) != 1 || capErr.ChunkTypes[0] != ChunkTypeScanCode {
+		t.Errorf("ChunkTypes = %v, want [scan_code]", capErr.ChunkTypes)
+	}
+}
@@ -0,0 +1,148 @@
+package landingai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/youssefsiam38/landingai/internal/miniyaml"
+)
+
+// ParseOptions is a reusable set of request options, such as an "invoice
+// profile" with a pinned model and page splitting. It is a plain value:
+// Clone and Merge return new options and never modify their receiver.
+type ParseOptions struct {
+	Model *Model     `json:"model,omitempty"`
+	Split *SplitType `json:"split,omitempty"`
+	// RequireChunkTypes and RequireGroundingTypes are checked against the model, see RequireChunkTypes
+	RequireChunkTypes     []ChunkType     `json:"require_chunk_types,omitempty"`
+	RequireGroundingTypes []GroundingType `json:"require_grounding_types,omitempty"`
+	// AutoIdempotencyKey derives an idempotency key from the document and options
+	AutoIdempotencyKey bool `json:"auto_idempotency_key,omitempty"`
+}
+
+// Clone returns a deep copy of the options
+func (o ParseOptions) Clone() ParseOptions {
+	if o.Model != nil {
+		model := *o.Model
+		o.Model = &model
+	}
+	if o.Split != nil {
+		split := *o.Split
+		o.Split = &split
+	}
+	o.RequireChunkTypes = append([]ChunkType(nil), o.RequireChunkTypes...)
+	o.RequireGroundingTypes = append([]GroundingType(nil), o.RequireGroundingTypes...)
+	return o
+}
+
+// Merge returns the options overridden by other: fields set in other win,
+// required types are combined and AutoIdempotencyKey is set if either sets it
+func (o ParseOptions) Merge(other ParseOptions) ParseOptions {
+	merged := o.Clone()
+	other = other.Clone()
+	if other.Model != nil {
+		merged.Model = other.Model
+	}
+	if other.Split != nil {
+		merged.Split = other.Split
+	}
+	for _, t := range other.RequireChunkTypes {
+		if !containsChunkType(merged.RequireChunkTypes, t) {
+			merged.RequireChunkTypes = append(merged.RequireChunkTypes, t)
+		}
+	}
+	for _, t := range other.RequireGroundingTypes {
+		if !containsGroundingType(merged.RequireGroundingTypes, t) {
+			merged.RequireGroundingTypes = append(merged.RequireGroundingTypes, t)
+		}
+	}
+	merged.AutoIdempotencyKey = merged.AutoIdempotencyKey || other.AutoIdempotencyKey
+	return merged
+}
+
+func containsChunkType(types []ChunkType, t ChunkType) bool {
+	for _, ct := range types {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsGroundingType(types []GroundingType, t GroundingType) bool {
+	for _, gt := range types {
+		if gt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply sets the options on the request. Fields not set in opts are left
+// unchanged, so several options can be layered, e.g. a profile then overrides.
+func (b *ParseRequestBuilder) Apply(opts ParseOptions) *ParseRequestBuilder {
+	b.req.ParseOptions = b.req.ParseOptions.Merge(opts)
+	return b
+}
+
+// ConfigFormat is the encoding of an options file
+type ConfigFormat string
+
+const (
+	ConfigJSON ConfigFormat = "json"
+	// ConfigYAML is the flat subset of YAML used by option files: "key: value"
+	// pairs with scalar or list values
+	ConfigYAML ConfigFormat = "yaml"
+)
+
+// LoadParseOptions reads options from a .json, .yaml or .yml file
+func LoadParseOptions(path string) (ParseOptions, error) {
+	var format ConfigFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = ConfigJSON
+	case ".yaml", ".yml":
+		format = ConfigYAML
+	default:
+		return ParseOptions{}, fmt.Errorf("unsupported options file %q: want .json, .yaml or .yml", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ParseOptions{}, fmt.Errorf("failed to read options file: %w", err)
+	}
+	opts, err := DecodeParseOptions(data, format)
+	if err != nil {
+		return ParseOptions{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return opts, nil
+}
+
+// DecodeParseOptions decodes options in the given format. Unknown keys are
+// rejected so typos in config files do not go unnoticed.
+func DecodeParseOptions(data []byte, format ConfigFormat) (ParseOptions, error) {
+	switch format {
+	case ConfigJSON:
+	case ConfigYAML:
+		fields, err := miniyaml.Unmarshal(data)
+		if err != nil {
+			return ParseOptions{}, fmt.Errorf("invalid options: %w", err)
+		}
+		if data, err = json.Marshal(fields); err != nil {
+			return ParseOptions{}, err
+		}
+	default:
+		return ParseOptions{}, fmt.Errorf("unsupported options format %q", format)
+	}
+
+	var opts ParseOptions
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&opts); err != nil {
+		return ParseOptions{}, fmt.Errorf("invalid options: %w", err)
+	}
+	return opts, nil
+}
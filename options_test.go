@@ -0,0 +1,120 @@
+package landingai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func invoiceProfile() ParseOptions {
+	model := ModelDPT220250919
+	split := SplitTypePage
+	return ParseOptions{
+		Model:             &model,
+		Split:             &split,
+		RequireChunkTypes: []ChunkType{ChunkTypeTable},
+	}
+}
+
+func TestParseOptions_Clone(t *testing.T) {
+	opts := invoiceProfile()
+	clone := opts.Clone()
+	*clone.Model = ModelDPT1Latest
+	clone.RequireChunkTypes[0] = ChunkTypeFigure
+
+	if *opts.Model != ModelDPT220250919 || opts.RequireChunkTypes[0] != ChunkTypeTable {
+		t.Errorf("modifying the clone changed the original: %+v", opts)
+	}
+}
+
+func TestParseOptions_Merge(t *testing.T) {
+	base := invoiceProfile()
+	override := ModelDPT2Latest
+	merged := base.Merge(ParseOptions{
+		Model:              &override,
+		RequireChunkTypes:  []ChunkType{ChunkTypeTable, ChunkTypeFigure},
+		AutoIdempotencyKey: true,
+	})
+
+	if *merged.Model != ModelDPT2Latest || *merged.Split != SplitTypePage {
+		t.Errorf("merged model/split = %s/%s", *merged.Model, *merged.Split)
+	}
+	if !reflect.DeepEqual(merged.RequireChunkTypes, []ChunkType{ChunkTypeTable, ChunkTypeFigure}) {
+		t.Errorf("merged chunk types = %v", merged.RequireChunkTypes)
+	}
+	if !merged.AutoIdempotencyKey {
+		t.Error("merged AutoIdempotencyKey = false")
+	}
+	if *base.Model != ModelDPT220250919 || len(base.RequireChunkTypes) != 1 {
+		t.Errorf("Merge modified the receiver: %+v", base)
+	}
+}
+
+func TestParseRequestBuilder_Apply(t *testing.T) {
+	var model, split string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		model, split = r.FormValue("model"), r.FormValue("split")
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	_, err := client.Parse(context.Background()).
+		WithURL("https://example.com/invoice.pdf").
+		Apply(invoiceProfile()).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if model != string(ModelDPT220250919) || split != "page" {
+		t.Errorf("sent model = %q, split = %q", model, split)
+	}
+}
+
+func TestLoadParseOptions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"invoice.json": `{"model":"dpt-2-20250919","split":"page","require_chunk_types":["table"]}`,
+		"invoice.yaml": "# Invoice profile\nmodel: dpt-2-20250919\nsplit: page\nrequire_chunk_types:\n  - table\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		opts, err := LoadParseOptions(path)
+		if err != nil {
+			t.Fatalf("LoadParseOptions(%s) error = %v", name, err)
+		}
+		if !reflect.DeepEqual(opts, invoiceProfile()) {
+			t.Errorf("LoadParseOptions(%s) = %+v", name, opts)
+		}
+	}
+}
+
+func TestDecodeParseOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format ConfigFormat
+		want   string
+	}{
+		{name: "unknown json key", data: `{"modle":"dpt-2-latest"}`, format: ConfigJSON, want: `unknown field "modle"`},
+		{name: "unknown yaml key", data: "modle: dpt-2-latest\n", format: ConfigYAML, want: `unknown field "modle"`},
+		{name: "nested yaml", data: "profile:\n  model: dpt-2-latest\n", format: ConfigYAML, want: "nested mappings"},
+		{name: "unknown format", data: "", format: "toml", want: "unsupported options format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeParseOptions([]byte(tt.data), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeParseOptions() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
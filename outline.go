@@ -0,0 +1,148 @@
+package landingai
+
+import (
+	"strings"
+
+	"github.com/youssefsiam38/landingai/internal/markdown"
+)
+
+// PageRange is an inclusive range of zero-indexed pages
+type PageRange struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+// Contains returns true if the page is within the range
+func (p PageRange) Contains(page int) bool {
+	return page >= p.First && page <= p.Last
+}
+
+// OutlineNode is a section of a document outline.
+// The root node has Level 0 and no heading.
+type OutlineNode struct {
+	Title    string         `json:"title"`
+	Level    int            `json:"level"`
+	ChunkID  string         `json:"chunk_id,omitempty"` // Chunk holding the heading
+	Chunks   []ParseChunk   `json:"chunks"`             // Body chunks directly under the heading, including the heading chunk if it carries body text
+	Children []*OutlineNode `json:"children"`
+	Pages    PageRange      `json:"pages"` // Pages covered by the section and its children
+}
+
+// Outline reconstructs the section tree of the document from title chunks and
+// markdown heading levels. Page headers, footers and page numbers are skipped.
+// A title chunk without a markdown heading is treated as a level 1 heading.
+func (r *ParseResponse) Outline() *OutlineNode {
+	root := &OutlineNode{Pages: PageRange{First: -1, Last: -1}}
+	stack := []*OutlineNode{root}
+
+	for _, chunk := range r.Chunks {
+		if r.isPageFurniture(chunk) {
+			continue
+		}
+
+		level, title := markdown.Heading(chunk.Markdown)
+		if level == 0 && r.GroundingTypeOf(chunk.ID) == GroundingTypeChunkTitle {
+			level = 1
+		}
+
+		if level == 0 {
+			parent := stack[len(stack)-1]
+			parent.Chunks = append(parent.Chunks, chunk)
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].Level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		node := &OutlineNode{
+			Title:   title,
+			Level:   level,
+			ChunkID: chunk.ID,
+			Pages:   PageRange{First: chunk.Grounding.Page, Last: chunk.Grounding.Page},
+		}
+		if hasBody(chunk.Markdown) {
+			// Keep text that shares a chunk with its heading in the section
+			node.Chunks = append(node.Chunks, chunk)
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+
+	root.updatePages()
+	return root
+}
+
+// hasBody returns true if the chunk has content after its first line
+func hasBody(md string) bool {
+	_, rest, _ := strings.Cut(markdown.Clean(md), "\n")
+	return strings.TrimSpace(rest) != ""
+}
+
+// isPageFurniture returns true for page headers, footers and page numbers
+func (r *ParseResponse) isPageFurniture(chunk ParseChunk) bool {
+	switch r.GroundingTypeOf(chunk.ID) {
+	case GroundingTypeChunkPageHeader, GroundingTypeChunkPageFooter, GroundingTypeChunkPageNumber:
+		return true
+	}
+	return ChunkType(chunk.Type) == ChunkTypeMarginalia
+}
+
+// updatePages expands the node's page range to cover its chunks and children
+func (n *OutlineNode) updatePages() {
+	extend := func(first, last int) {
+		if first < 0 {
+			return
+		}
+		if n.Pages.First < 0 || first < n.Pages.First {
+			n.Pages.First = first
+		}
+		if last > n.Pages.Last {
+			n.Pages.Last = last
+		}
+	}
+	for _, c := range n.Chunks {
+		extend(c.Grounding.Page, c.Grounding.Page)
+	}
+	for _, child := range n.Children {
+		child.updatePages()
+		extend(child.Pages.First, child.Pages.Last)
+	}
+}
+
+// Walk visits the node and its descendants depth-first.
+// Returning false from fn skips the node's children.
+func (n *OutlineNode) Walk(fn func(node *OutlineNode, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n *OutlineNode) walk(fn func(*OutlineNode, int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// AllChunks returns the body chunks of the section and all of its children in document order
+func (n *OutlineNode) AllChunks() []ParseChunk {
+	var chunks []ParseChunk
+	n.Walk(func(node *OutlineNode, _ int) bool {
+		chunks = append(chunks, node.Chunks...)
+		return true
+	})
+	return chunks
+}
+
+// Find returns the first section whose title matches exactly, or nil if none does
+func (n *OutlineNode) Find(title string) *OutlineNode {
+	var found *OutlineNode
+	n.Walk(func(node *OutlineNode, _ int) bool {
+		if found == nil && node.Level > 0 && node.Title == title {
+			found = node
+		}
+		return found == nil
+	})
+	return found
+}
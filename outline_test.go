@@ -0,0 +1,75 @@
+package landingai
+
+import "testing"
+
+func TestParseResponse_Outline(t *testing.T) {
+	resp := &ParseResponse{
+		Chunks: []ParseChunk{
+			{ID: "hdr", Type: "text", Markdown: "ACME Corp", Grounding: ParseGrounding{Page: 0}},
+			{ID: "t1", Type: "text", Markdown: "<a id='t1'></a>\n\n# Report", Grounding: ParseGrounding{Page: 0}},
+			{ID: "p1", Type: "text", Markdown: "Intro", Grounding: ParseGrounding{Page: 0}},
+			{ID: "t2", Type: "text", Markdown: "## Revenue", Grounding: ParseGrounding{Page: 1}},
+			{ID: "p2", Type: "table", Markdown: "| a | b |\n|---|---|\n| 1 | 2 |", Grounding: ParseGrounding{Page: 2}},
+			{ID: "t3", Type: "text", Markdown: "Appendix", Grounding: ParseGrounding{Page: 3}},
+			{ID: "ftr", Type: "marginalia", Markdown: "Page 4", Grounding: ParseGrounding{Page: 3}},
+		},
+		Grounding: map[string]ParseResponseGrounding{
+			"hdr": {Type: GroundingTypeChunkPageHeader},
+			"t3":  {Type: GroundingTypeChunkTitle},
+		},
+	}
+
+	root := resp.Outline()
+	if len(root.Children) != 2 {
+		t.Fatalf("root has %d children, want 2", len(root.Children))
+	}
+	if len(root.Chunks) != 0 {
+		t.Errorf("root has %d chunks, want page header skipped", len(root.Chunks))
+	}
+
+	report := root.Children[0]
+	if report.Title != "Report" || report.Level != 1 {
+		t.Errorf("first section = %q level %d, want Report level 1", report.Title, report.Level)
+	}
+	if report.Pages != (PageRange{First: 0, Last: 2}) {
+		t.Errorf("Report pages = %+v, want 0-2", report.Pages)
+	}
+
+	revenue := root.Find("Revenue")
+	if revenue == nil || len(revenue.Chunks) != 1 || revenue.Chunks[0].ID != "p2" {
+		t.Fatalf("Find(Revenue) = %+v", revenue)
+	}
+	if got := len(report.AllChunks()); got != 2 {
+		t.Errorf("Report AllChunks() = %d chunks, want 2", got)
+	}
+
+	appendix := root.Children[1]
+	if appendix.Title != "Appendix" || len(appendix.Chunks) != 0 {
+		t.Errorf("title chunk section = %+v", appendix)
+	}
+}
+
+func TestParseResponse_OutlineHeadingWithBody(t *testing.T) {
+	resp := &ParseResponse{
+		Chunks: []ParseChunk{
+			{ID: "t1", Type: "text", Markdown: "# Contract"},
+			{ID: "t2", Type: "text", Markdown: "## Terms\n\nPayment is due within 30 days.", Grounding: ParseGrounding{Page: 2}},
+			{ID: "t3", Type: "text", Markdown: "## Signatures\n"},
+		},
+	}
+
+	root := resp.Outline()
+	terms := root.Find("Terms")
+	if terms == nil || len(terms.Chunks) != 1 || terms.Chunks[0].ID != "t2" {
+		t.Fatalf("Find(Terms) = %+v, want heading chunk kept as body", terms)
+	}
+	if terms.Pages != (PageRange{First: 2, Last: 2}) {
+		t.Errorf("Terms pages = %+v, want 2-2", terms.Pages)
+	}
+	if signatures := root.Find("Signatures"); signatures == nil || len(signatures.Chunks) != 0 {
+		t.Errorf("Find(Signatures) = %+v, want no body chunks", signatures)
+	}
+	if got := root.Find("Contract").AllChunks(); len(got) != 1 || got[0].ID != "t2" {
+		t.Errorf("Contract AllChunks() = %+v, want the Terms body", got)
+	}
+}
@@ -4,129 +4,346 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
+	"time"
 )
 
-// ParseRequestBuilder is a builder for Parse API requests
+// ParseRequestBuilder is a builder for Parse API requests.
+// It is a thin layer over ParseRequest; use Build to get the request as a value.
 type ParseRequestBuilder struct {
-	client      *Client
-	ctx         context.Context
-	model       *string
-	documentURL *string
-	filePath    string
-	fileData    []byte
-	fileName    string
-	split       *SplitType
+	client *Client
+	ctx    context.Context
+	req    ParseRequest
+
+	progress func(Progress)
 }
 
 // WithModel sets the model version to use for parsing
-// Examples: "dpt-2-latest", "dpt-2-20250919", "dpt-1-latest", "DPT-2-mini-latest"
-func (b *ParseRequestBuilder) WithModel(model string) *ParseRequestBuilder {
-	b.model = &model
+// Examples: ModelDPT2Latest, ModelDPT220250919, ModelDPT1Latest, ModelDPT2MiniLatest
+func (b *ParseRequestBuilder) WithModel(model Model) *ParseRequestBuilder {
+	b.req.Model = &model
 	return b
 }
 
 // WithURL sets the document URL to parse
 func (b *ParseRequestBuilder) WithURL(url string) *ParseRequestBuilder {
-	b.documentURL = &url
+	b.req.DocumentURL = &url
 	return b
 }
 
 // WithFile sets the file path to upload and parse
 func (b *ParseRequestBuilder) WithFile(filePath string) *ParseRequestBuilder {
-	b.filePath = filePath
+	b.clearSource()
+	b.req.FilePath = filePath
 	return b
 }
 
 // WithFileData sets the file data directly (with filename)
 func (b *ParseRequestBuilder) WithFileData(data []byte, filename string) *ParseRequestBuilder {
-	b.fileData = data
-	b.fileName = filename
+	b.clearSource()
+	if data == nil {
+		data = []byte{}
+	}
+	b.req.Document, b.req.DocumentName = data, filename
+	return b
+}
+
+// WithSource sets the source of the document to upload and parse.
+// The document is read locally and streamed into the upload.
+func (b *ParseRequestBuilder) WithSource(src DocumentSource) *ParseRequestBuilder {
+	b.clearSource()
+	b.req.Source = src
+	return b
+}
+
+// WithSourceURI fetches the document at uri locally and uploads it.
+// "file", "http" and "https" URIs are built in; other schemes such as "s3://"
+// or "gs://" need a fetcher registered with WithFetcher. Unlike WithURL, the
+// API never sees the URI, so it works for private storage.
+func (b *ParseRequestBuilder) WithSourceURI(uri string) *ParseRequestBuilder {
+	b.clearSource()
+	b.req.SourceURI = uri
 	return b
 }
 
+// clearSource unsets the uploaded document, so the last source set wins
+func (b *ParseRequestBuilder) clearSource() {
+	b.req.FilePath, b.req.Document, b.req.DocumentName = "", nil, ""
+	b.req.SourceURI, b.req.Source = "", nil
+}
+
 // WithSplit enables document splitting at the specified level
 func (b *ParseRequestBuilder) WithSplit(split SplitType) *ParseRequestBuilder {
-	b.split = &split
+	b.req.Split = &split
 	return b
 }
 
 // WithPageSplit is a convenience method to enable page-level splitting
 func (b *ParseRequestBuilder) WithPageSplit() *ParseRequestBuilder {
-	split := SplitTypePage
-	b.split = &split
+	return b.WithSplit(SplitTypePage)
+}
+
+// RequireChunkTypes declares chunk types the caller depends on.
+// Do checks them against the chosen model's capabilities.
+func (b *ParseRequestBuilder) RequireChunkTypes(types ...ChunkType) *ParseRequestBuilder {
+	b.req.RequireChunkTypes = append(b.req.RequireChunkTypes, types...)
+	return b
+}
+
+// RequireGroundingTypes declares grounding types the caller depends on.
+// Do checks them against the chosen model's capabilities.
+func (b *ParseRequestBuilder) RequireGroundingTypes(types ...GroundingType) *ParseRequestBuilder {
+	b.req.RequireGroundingTypes = append(b.req.RequireGroundingTypes, types...)
 	return b
 }
 
-// Do executes the parse request
+// Do executes the parse request.
+// Concurrent requests on the same client with the same idempotency key and
+// document are collapsed into one HTTP call, and every caller receives its own
+// copy of the response. The shared call is not cancelled when one caller's
+// context is done, only when every caller has given up.
 func (b *ParseRequestBuilder) Do() (*ParseResponse, error) {
+	key, err := b.prepare()
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return b.decodeResponse("")
+	}
+
+	// Derived keys already identify the payload
+	payload := key
+	if b.req.IdempotencyKey != "" {
+		payload = b.req.payloadID(b.client)
+	}
+	return b.client.inflight.do(b.ctx, key, payload, func(ctx context.Context) (*ParseResponse, error) {
+		shared := *b
+		shared.ctx = ctx
+		return shared.decodeResponse(key)
+	})
+}
+
+// prepare validates the request and resolves its idempotency key
+func (b *ParseRequestBuilder) prepare() (string, error) {
 	// Validate inputs
-	if b.documentURL != nil && (b.filePath != "" || b.fileData != nil) {
-		return nil, fmt.Errorf("cannot provide both document URL and file")
+	if err := b.req.Validate(); err != nil {
+		return "", err
+	}
+	if err := b.checkCapabilities(); err != nil {
+		return "", err
+	}
+	return b.resolveIdempotencyKey()
+}
+
+// decodeResponse executes the request and decodes the whole response body
+func (b *ParseRequestBuilder) decodeResponse(idempotencyKey string) (*ParseResponse, error) {
+	var parseResp *ParseResponse
+	err := b.execute(idempotencyKey, func(info *ResponseInfo, body io.Reader) error {
+		// Read response body
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return &TransportError{Op: opReadResponseBody, Err: err}
+		}
+
+		// Parse successful response
+		var r ParseResponse
+		if err := json.Unmarshal(data, &r); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		r.raw, r.http = data, info
+		parseResp = &r
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if b.documentURL == nil && b.filePath == "" && b.fileData == nil {
-		return nil, fmt.Errorf("must provide either document URL or file")
+	return parseResp, nil
+}
+
+// execute sends the request through the circuit breaker, if any, and passes
+// the body of a successful response to decode
+func (b *ParseRequestBuilder) execute(idempotencyKey string, decode func(info *ResponseInfo, body io.Reader) error) error {
+	breaker := b.client.breaker
+	if breaker == nil {
+		return b.send(idempotencyKey, decode)
+	}
+
+	// Fail fast while the circuit is open
+	ticket, ok, retryAfter := breaker.allow()
+	if !ok {
+		return &CircuitOpenError{RetryAfter: retryAfter}
+	}
+	err := b.send(idempotencyKey, decode)
+	breaker.record(ticket, err)
+	return err
+}
+
+// send builds and executes the HTTP request and decodes the response.
+// If the API rejects the key with 401 and the credentials provider can be
+// invalidated, the key is re-fetched and the request retried once. If the
+// retry cannot be built, e.g. because a single-use source was consumed, the
+// original 401 is returned.
+func (b *ParseRequestBuilder) send(idempotencyKey string, decode func(info *ResponseInfo, body io.Reader) error) error {
+	apiKey, err := b.sendOnce(idempotencyKey, 1, decode)
+	if !isUnauthorized(err) {
+		return err
+	}
+
+	inv, ok := b.client.credentials.(CredentialsInvalidator)
+	if !ok {
+		return err
+	}
+	inv.Invalidate()
+	newKey, keyErr := b.client.credentials.APIKey(b.ctx)
+	if keyErr != nil || newKey == apiKey {
+		// The key did not rotate, so retrying would fail the same way
+		return err
+	}
+	_, retryErr := b.sendOnce(idempotencyKey, 2, decode)
+	var buildErr *requestBuildError
+	if errors.As(retryErr, &buildErr) {
+		return err
+	}
+	return retryErr
+}
+
+// requestBuildError is returned by sendOnce when the request could not be built
+type requestBuildError struct {
+	err error
+}
+
+func (e *requestBuildError) Error() string {
+	return "failed to build request: " + e.err.Error()
+}
+
+func (e *requestBuildError) Unwrap() error {
+	return e.err
+}
+
+// sendOnce executes a single attempt, returning the API key it used
+func (b *ParseRequestBuilder) sendOnce(idempotencyKey string, attempt int, decode func(info *ResponseInfo, body io.Reader) error) (string, error) {
+	apiKey, err := b.client.credentials.APIKey(b.ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get API key: %w", err)
 	}
 
 	// Create the request
-	req, err := b.buildRequest()
+	tracker := newProgressTracker(b.progress)
+	req, err := b.buildRequest(apiKey, tracker)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build request: %w", err)
+		return apiKey, &requestBuildError{err: err}
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
 	}
 
 	// Execute the request
+	start := time.Now()
 	resp, err := b.client.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return apiKey, &TransportError{Op: opExecuteRequest, Err: err, ErrorContext: ErrorContext{Attempt: attempt, Tags: b.req.Tags}}
 	}
 	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+	info := newResponseInfo(resp, time.Since(start))
+	info.Tags = b.req.Tags
+	errCtx := ErrorContext{RequestID: info.RequestID, Attempt: attempt, Tags: b.req.Tags}
+	b.logger().Debug("landingai: parse response received", "status", info.StatusCode, "request_id", info.RequestID, "latency", info.Latency, "attempt", attempt)
 
 	// Handle errors
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, b.handleErrorResponse(resp.StatusCode, body)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return apiKey, &TransportError{Op: opReadResponseBody, Err: err, ErrorContext: errCtx}
+		}
+		return apiKey, b.handleErrorResponse(resp.StatusCode, body, errCtx)
 	}
 
-	// Parse successful response
-	var parseResp ParseResponse
-	if err := json.Unmarshal(body, &parseResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	tracker.downloading(resp.ContentLength)
+	if err := decode(info, tracker.downloadReader(resp.Body)); err != nil {
+		var transportErr *TransportError
+		if errors.As(err, &transportErr) {
+			transportErr.ErrorContext = errCtx
+		}
+		return apiKey, err
 	}
+	tracker.done()
+	return apiKey, nil
+}
 
-	return &parseResp, nil
+// isUnauthorized reports whether err is a 401 from the API
+func isUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsUnauthorized()
+}
+
+// checkCapabilities verifies the chosen model supports the features the request depends on
+func (b *ParseRequestBuilder) checkCapabilities() error {
+	if b.client.capCheck == CapabilityCheckOff {
+		return nil
+	}
+
+	model := ModelDPT2Latest
+	if b.req.Model != nil {
+		model = *b.req.Model
+	}
+
+	caps, ok := model.Capabilities()
+	if !ok {
+		// Newer models may be unknown to this SDK version, so never fail on them
+		b.logger().Warn("landingai: unknown model, skipping capability check", "model", model)
+		return nil
+	}
+
+	capErr := &CapabilityError{Model: model}
+	for _, t := range b.req.RequireChunkTypes {
+		if !caps.SupportsChunkType(t) {
+			capErr.ChunkTypes = append(capErr.ChunkTypes, t)
+		}
+	}
+	for _, t := range b.req.RequireGroundingTypes {
+		if !caps.SupportsGroundingType(t) {
+			capErr.GroundingTypes = append(capErr.GroundingTypes, t)
+		}
+	}
+	capErr.Split = b.req.Split != nil && !caps.Split
+
+	if len(capErr.ChunkTypes) == 0 && len(capErr.GroundingTypes) == 0 && !capErr.Split {
+		return nil
+	}
+	if b.client.capCheck == CapabilityCheckError {
+		return capErr
+	}
+	b.logger().Warn("landingai: "+capErr.Error(), "model", model)
+	return nil
 }
 
 // buildRequest constructs the HTTP request
-func (b *ParseRequestBuilder) buildRequest() (*http.Request, error) {
+func (b *ParseRequestBuilder) buildRequest(apiKey string, tracker *progressTracker) (*http.Request, error) {
 	url := fmt.Sprintf("%s/v1/ade/parse", b.client.baseURL)
 
 	var req *http.Request
 	var err error
 
-	if b.documentURL != nil {
+	if b.req.DocumentURL != nil {
 		// URL-based request
-		req, err = b.buildURLRequest(url)
+		req, err = b.buildURLRequest(url, tracker)
 	} else {
 		// File-based request
-		req, err = b.buildFileRequest(url)
+		req, err = b.buildFileRequest(url, tracker)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	b.setHeaders(req)
+
 	// Add authorization header
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.client.apiKey))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 
 	// Set context
 	req = req.WithContext(b.ctx)
@@ -135,23 +352,23 @@ func (b *ParseRequestBuilder) buildRequest() (*http.Request, error) {
 }
 
 // buildURLRequest builds a request with document_url
-func (b *ParseRequestBuilder) buildURLRequest(url string) (*http.Request, error) {
+func (b *ParseRequestBuilder) buildURLRequest(url string, tracker *progressTracker) (*http.Request, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	// Add document_url
-	if err := writer.WriteField("document_url", *b.documentURL); err != nil {
+	if err := writer.WriteField("document_url", *b.req.DocumentURL); err != nil {
 		return nil, err
 	}
 
 	// Add optional fields
-	if b.model != nil {
-		if err := writer.WriteField("model", *b.model); err != nil {
+	if b.req.Model != nil {
+		if err := writer.WriteField("model", string(*b.req.Model)); err != nil {
 			return nil, err
 		}
 	}
-	if b.split != nil {
-		if err := writer.WriteField("split", string(*b.split)); err != nil {
+	if b.req.Split != nil {
+		if err := writer.WriteField("split", string(*b.req.Split)); err != nil {
 			return nil, err
 		}
 	}
@@ -164,84 +381,103 @@ func (b *ParseRequestBuilder) buildURLRequest(url string) (*http.Request, error)
 	if err != nil {
 		return nil, err
 	}
+	if tracker != nil {
+		tracker.uploading(req.ContentLength)
+		req.Body = &progressBody{r: tracker.uploadReader(body), c: req.Body, tracker: tracker}
+		req.GetBody = nil
+	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	return req, nil
 }
 
-// buildFileRequest builds a request with file upload
-func (b *ParseRequestBuilder) buildFileRequest(url string) (*http.Request, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+// buildFileRequest builds a request that streams the document source as a multipart upload
+func (b *ParseRequestBuilder) buildFileRequest(url string, tracker *progressTracker) (*http.Request, error) {
+	// Open the source up front so missing documents fail before anything is sent
+	src, _ := b.req.source(b.client)
+	doc, fileName, err := src.Open(b.ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracker.uploading(documentSize(src, doc))
 
-	// Determine file data and name
-	var fileData []byte
-	var fileName string
-	var err error
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
 
-	if b.fileData != nil {
-		fileData = b.fileData
-		fileName = b.fileName
-	} else {
-		// Read file from path
-		fileData, err = os.ReadFile(b.filePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read file: %w", err)
+	go func() {
+		defer doc.Close()
+		err := b.writeFileBody(writer, tracker.uploadReader(sourceReader{doc}), fileName)
+		if err == nil {
+			tracker.processing()
 		}
-		fileName = filepath.Base(b.filePath)
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(b.ctx, "POST", url, pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
 	}
 
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req, nil
+}
+
+// writeFileBody writes the multipart body of a file upload
+func (b *ParseRequestBuilder) writeFileBody(writer *multipart.Writer, doc io.Reader, fileName string) error {
 	// Add file
 	part, err := writer.CreateFormFile("document", fileName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if _, err = part.Write(fileData); err != nil {
-		return nil, err
+	if _, err = io.Copy(part, doc); err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
 	}
 
 	// Add optional fields
-	if b.model != nil {
-		err = writer.WriteField("model", *b.model)
+	if b.req.Model != nil {
+		err = writer.WriteField("model", string(*b.req.Model))
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	if b.split != nil {
-		err = writer.WriteField("split", string(*b.split))
+	if b.req.Split != nil {
+		err = writer.WriteField("split", string(*b.req.Split))
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	err = writer.Close()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(b.ctx, "POST", url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	return req, nil
+	return writer.Close()
 }
 
 // handleErrorResponse processes error responses from the API
-func (b *ParseRequestBuilder) handleErrorResponse(statusCode int, body []byte) error {
+func (b *ParseRequestBuilder) handleErrorResponse(statusCode int, body []byte, errCtx ErrorContext) error {
+	// Pick up the job ID if the API reports one
+	var job struct {
+		JobID string `json:"job_id"`
+	}
+	if json.Unmarshal(body, &job) == nil {
+		errCtx.JobID = job.JobID
+	}
+
 	// Try to parse as validation error
 	if statusCode == StatusUnprocessableEntity {
 		var valErr ValidationErrors
 		if err := json.Unmarshal(body, &valErr); err == nil {
+			valErr.ErrorContext = errCtx
+			for i := range valErr.Detail {
+				valErr.Detail[i].Method = b.methodFor(valErr.Detail[i].Path().Field())
+			}
 			return &valErr
 		}
 	}
 
 	// Create generic API error
 	apiErr := &APIError{
-		StatusCode: statusCode,
-		Message:    getErrorMessage(statusCode),
+		StatusCode:   statusCode,
+		Message:      getErrorMessage(statusCode),
+		ErrorContext: errCtx,
 	}
 
 	// Try to extract detail from body
@@ -258,6 +494,15 @@ func (b *ParseRequestBuilder) handleErrorResponse(statusCode int, body []byte) e
 	return apiErr
 }
 
+// methodFor returns the builder method that set an API request field
+func (b *ParseRequestBuilder) methodFor(field string) string {
+	if _, method := b.req.source(b.client); field == "document" && method != "" {
+		return method
+	}
+	method, _ := BuilderMethod(field)
+	return method
+}
+
 // getErrorMessage returns a user-friendly error message for common status codes
 func getErrorMessage(statusCode int) string {
 	switch statusCode {
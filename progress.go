@@ -0,0 +1,312 @@
+package landingai
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProgressPhase is the stage a parse request is in
+type ProgressPhase string
+
+const (
+	// ProgressUploading is reported while the document is sent
+	ProgressUploading ProgressPhase = "uploading"
+	// ProgressProcessing is reported once the upload is complete and the API is parsing
+	ProgressProcessing ProgressPhase = "processing"
+	// ProgressDownloading is reported while the response body is received
+	ProgressDownloading ProgressPhase = "downloading"
+	// ProgressDone is reported once the response has been read
+	ProgressDone ProgressPhase = "done"
+)
+
+// Progress is a snapshot of a parse request's progress
+type Progress struct {
+	Phase ProgressPhase
+	// BytesSent is the number of upload bytes sent so far. For file uploads it
+	// counts document bytes; for URL requests the whole request body.
+	BytesSent int64
+	// TotalBytes is the upload size, or -1 if unknown (e.g. ReaderSource)
+	TotalBytes int64
+	// BytesReceived is the number of response bytes read so far
+	BytesReceived int64
+	// ResponseBytes is the response size, or -1 if the server did not send Content-Length
+	ResponseBytes int64
+	// Elapsed is the time since the request started
+	Elapsed time.Duration
+}
+
+// Fraction returns the completed fraction of the current phase, between 0 and 1,
+// or -1 if the total is unknown. Processing is reported as -1.
+func (p Progress) Fraction() float64 {
+	switch p.Phase {
+	case ProgressUploading:
+		return fraction(p.BytesSent, p.TotalBytes)
+	case ProgressDownloading:
+		return fraction(p.BytesReceived, p.ResponseBytes)
+	case ProgressDone:
+		return 1
+	}
+	return -1
+}
+
+func fraction(n, total int64) float64 {
+	if total < 0 {
+		return -1
+	}
+	if total == 0 || n >= total {
+		return 1
+	}
+	return float64(n) / float64(total)
+}
+
+// WithProgress reports the progress of the request to fn: upload bytes sent,
+// then the processing phase, then response bytes received. fn is called
+// synchronously from the goroutines doing the I/O, one call at a time, so it
+// should return quickly. When requests are deduplicated by idempotency key,
+// only the caller that sends the request receives progress.
+func (b *ParseRequestBuilder) WithProgress(fn func(Progress)) *ParseRequestBuilder {
+	b.progress = fn
+	return b
+}
+
+// progressTracker serializes progress updates for one attempt.
+// A nil tracker ignores all updates.
+type progressTracker struct {
+	mu    sync.Mutex
+	fn    func(Progress)
+	start time.Time
+	p     Progress
+}
+
+func newProgressTracker(fn func(Progress)) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	return &progressTracker{
+		fn:    fn,
+		start: time.Now(),
+		p:     Progress{Phase: ProgressUploading, TotalBytes: -1, ResponseBytes: -1},
+	}
+}
+
+// update applies change and reports the new snapshot
+func (t *progressTracker) update(change func(p *Progress)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	change(&t.p)
+	t.p.Elapsed = time.Since(t.start)
+	t.fn(t.p)
+}
+
+// uploading starts the upload phase with the given total size
+func (t *progressTracker) uploading(total int64) {
+	t.update(func(p *Progress) { p.TotalBytes = total })
+}
+
+// processing marks the upload as complete
+func (t *progressTracker) processing() {
+	t.update(func(p *Progress) {
+		if p.TotalBytes < 0 {
+			p.TotalBytes = p.BytesSent
+		}
+		p.Phase = ProgressProcessing
+	})
+}
+
+// downloading starts the download phase with the given response size
+func (t *progressTracker) downloading(total int64) {
+	t.update(func(p *Progress) {
+		p.Phase = ProgressDownloading
+		p.ResponseBytes = total
+	})
+}
+
+func (t *progressTracker) done() {
+	t.update(func(p *Progress) { p.Phase = ProgressDone })
+}
+
+// uploadReader counts bytes read from r as sent
+func (t *progressTracker) uploadReader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &countingReader{r: r, add: func(n int64) {
+		t.update(func(p *Progress) { p.BytesSent += n })
+	}}
+}
+
+// downloadReader counts bytes read from r as received
+func (t *progressTracker) downloadReader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &countingReader{r: r, add: func(n int64) {
+		t.update(func(p *Progress) { p.BytesReceived += n })
+	}}
+}
+
+// countingReader reports the number of bytes of each read
+type countingReader struct {
+	r   io.Reader
+	add func(n int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.add(int64(n))
+	}
+	return n, err
+}
+
+// progressBody is a request body that reports the upload as complete once drained
+type progressBody struct {
+	r       io.Reader
+	c       io.Closer
+	tracker *progressTracker
+	drained bool
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF && !b.drained {
+		b.drained = true
+		b.tracker.processing()
+	}
+	return n, err
+}
+
+func (b *progressBody) Close() error {
+	return b.c.Close()
+}
+
+// documentSize returns the size of an opened document, or -1 if unknown
+func documentSize(src DocumentSource, rc io.Reader) int64 {
+	switch s := src.(type) {
+	case BytesSource:
+		return int64(len(s.Data))
+	case *BytesSource:
+		return int64(len(s.Data))
+	}
+	if f, ok := rc.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
+}
+
+// BatchProgress is the aggregate progress of several parse requests
+type BatchProgress struct {
+	// Total is the number of requests in the batch
+	Total int
+	// Completed and Failed count finished requests
+	Completed int
+	Failed    int
+	// BytesSent and TotalBytes sum the uploads of all requests whose size is known
+	BytesSent  int64
+	TotalBytes int64
+	// Items holds the latest progress of each request, by index.
+	// Finished requests, failed or not, are in the ProgressDone phase.
+	Items []Progress
+}
+
+// Fraction returns the overall completed fraction, between 0 and 1. Finished
+// requests count fully; in-flight requests count up to half while uploading
+// and half once the upload is complete.
+func (b BatchProgress) Fraction() float64 {
+	if b.Total == 0 {
+		return 1
+	}
+	var done float64
+	for _, item := range b.Items {
+		switch item.Phase {
+		case ProgressDone:
+			done++
+		case ProgressUploading:
+			if f := item.Fraction(); f > 0 {
+				done += f / 2
+			}
+		default:
+			done += 0.5
+		}
+	}
+	return done / float64(b.Total)
+}
+
+// ProgressGroup aggregates the progress of a batch of parse requests.
+// Pass Track(i) to WithProgress for the i-th request and call Finish when it
+// returns; fn receives the aggregate after every change, one call at a time.
+type ProgressGroup struct {
+	mu        sync.Mutex
+	fn        func(BatchProgress)
+	items     []Progress
+	finished  []bool
+	completed int
+	failed    int
+}
+
+// NewProgressGroup creates a group for n requests
+func NewProgressGroup(n int, fn func(BatchProgress)) *ProgressGroup {
+	items := make([]Progress, n)
+	for i := range items {
+		items[i] = Progress{Phase: ProgressUploading, TotalBytes: -1, ResponseBytes: -1}
+	}
+	return &ProgressGroup{fn: fn, items: items, finished: make([]bool, n)}
+}
+
+// Track returns the progress callback for the i-th request
+func (g *ProgressGroup) Track(i int) func(Progress) {
+	return func(p Progress) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.finished[i] {
+			return
+		}
+		if p.Phase == ProgressDone {
+			// Completion is counted by Finish
+			p.Phase = ProgressDownloading
+		}
+		g.items[i] = p
+		g.report()
+	}
+}
+
+// Finish marks the i-th request as finished, failed if err is non-nil
+func (g *ProgressGroup) Finish(i int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.finished[i] {
+		return
+	}
+	g.finished[i] = true
+	if err != nil {
+		g.failed++
+	} else {
+		g.completed++
+	}
+	g.items[i].Phase = ProgressDone
+	g.report()
+}
+
+// report sends the aggregate to fn; the caller holds g.mu
+func (g *ProgressGroup) report() {
+	bp := BatchProgress{
+		Total:     len(g.items),
+		Completed: g.completed,
+		Failed:    g.failed,
+		Items:     append([]Progress(nil), g.items...),
+	}
+	for _, item := range g.items {
+		if item.TotalBytes >= 0 {
+			bp.BytesSent += item.BytesSent
+			bp.TotalBytes += item.TotalBytes
+		}
+	}
+	g.fn(bp)
+}
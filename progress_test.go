@@ -0,0 +1,147 @@
+package landingai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+const progressResponse = `{"markdown":"# Progress","metadata":{"page_count":1}}`
+
+func progressServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		w.Header().Set("Content-Length", strconv.Itoa(len(progressResponse)))
+		w.Write([]byte(progressResponse))
+	}))
+}
+
+// phases returns the distinct phases of updates, in order
+func phases(updates []Progress) []ProgressPhase {
+	var out []ProgressPhase
+	for _, p := range updates {
+		if len(out) == 0 || out[len(out)-1] != p.Phase {
+			out = append(out, p.Phase)
+		}
+	}
+	return out
+}
+
+func TestParseRequestBuilder_WithProgress(t *testing.T) {
+	server := progressServer(t)
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	document := bytes.Repeat([]byte("x"), 256<<10)
+	var updates []Progress
+	_, err := client.Parse(context.Background()).
+		WithFileData(document, "big.pdf").
+		WithProgress(func(p Progress) { updates = append(updates, p) }).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	want := []ProgressPhase{ProgressUploading, ProgressProcessing, ProgressDownloading, ProgressDone}
+	if got := phases(updates); !slices.Equal(got, want) {
+		t.Fatalf("phases = %v, want %v", got, want)
+	}
+	var sent int64
+	for _, p := range updates {
+		if p.BytesSent < sent {
+			t.Fatalf("BytesSent went backwards: %d after %d", p.BytesSent, sent)
+		}
+		sent = p.BytesSent
+	}
+	last := updates[len(updates)-1]
+	if last.BytesSent != int64(len(document)) || last.TotalBytes != int64(len(document)) {
+		t.Errorf("upload = %d/%d, want %d", last.BytesSent, last.TotalBytes, len(document))
+	}
+	if last.BytesReceived != int64(len(progressResponse)) || last.ResponseBytes != int64(len(progressResponse)) {
+		t.Errorf("download = %d/%d, want %d", last.BytesReceived, last.ResponseBytes, len(progressResponse))
+	}
+	if last.Fraction() != 1 {
+		t.Errorf("Fraction() = %v, want 1", last.Fraction())
+	}
+}
+
+func TestParseRequestBuilder_WithProgressURL(t *testing.T) {
+	server := progressServer(t)
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	var updates []Progress
+	_, err := client.Parse(context.Background()).
+		WithURL("https://example.com/doc.pdf").
+		WithProgress(func(p Progress) { updates = append(updates, p) }).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	want := []ProgressPhase{ProgressUploading, ProgressProcessing, ProgressDownloading, ProgressDone}
+	if got := phases(updates); !slices.Equal(got, want) {
+		t.Fatalf("phases = %v, want %v", got, want)
+	}
+	last := updates[len(updates)-1]
+	if last.TotalBytes <= 0 || last.BytesSent != last.TotalBytes {
+		t.Errorf("upload = %d/%d, want the whole request body", last.BytesSent, last.TotalBytes)
+	}
+}
+
+func TestParseRequestBuilder_WithProgressUnknownSize(t *testing.T) {
+	server := progressServer(t)
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	var first, last Progress
+	_, err := client.Parse(context.Background()).
+		WithSource(ReaderSource(bytes.NewReader([]byte("%PDF-1.4")), "stream.pdf")).
+		WithProgress(func(p Progress) {
+			if first.Phase == "" {
+				first = p
+			}
+			last = p
+		}).
+		Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if first.TotalBytes != -1 || first.Fraction() != -1 {
+		t.Errorf("first update = %+v, want unknown total", first)
+	}
+	if last.TotalBytes != 8 {
+		t.Errorf("TotalBytes after upload = %d, want 8", last.TotalBytes)
+	}
+}
+
+func TestProgressGroup(t *testing.T) {
+	var latest BatchProgress
+	group := NewProgressGroup(2, func(bp BatchProgress) { latest = bp })
+
+	group.Track(0)(Progress{Phase: ProgressUploading, BytesSent: 50, TotalBytes: 100})
+	group.Track(1)(Progress{Phase: ProgressUploading, BytesSent: 0, TotalBytes: -1})
+	if latest.BytesSent != 50 || latest.TotalBytes != 100 {
+		t.Errorf("bytes = %d/%d, want 50/100", latest.BytesSent, latest.TotalBytes)
+	}
+	if got := latest.Fraction(); got != 0.125 {
+		t.Errorf("Fraction() = %v, want 0.125", got)
+	}
+
+	group.Track(0)(Progress{Phase: ProgressDone, BytesSent: 100, TotalBytes: 100})
+	if latest.Completed != 0 || latest.Fraction() != 0.25 {
+		t.Errorf("done before Finish: completed = %d, fraction = %v", latest.Completed, latest.Fraction())
+	}
+	group.Finish(0, nil)
+	group.Finish(1, errors.New("boom"))
+	group.Track(1)(Progress{Phase: ProgressUploading})
+	if latest.Completed != 1 || latest.Failed != 1 || latest.Fraction() != 1 {
+		t.Errorf("latest = %+v, want 1 completed, 1 failed, fraction 1", latest)
+	}
+}
@@ -0,0 +1,46 @@
+package rag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Record is a single JSONL line in the layout most vector stores ingest:
+// an ID, the text to embed and a flat metadata object
+type Record struct {
+	ID       string         `json:"id"`
+	Text     string         `json:"text"`
+	Metadata RecordMetadata `json:"metadata"`
+}
+
+// RecordMetadata carries the provenance of a passage
+type RecordMetadata struct {
+	Source   string    `json:"source,omitempty"`
+	ChunkIDs []string  `json:"chunk_ids"`
+	Pages    []int     `json:"pages"`
+	Boxes    []PageBox `json:"boxes"`
+	Tokens   int       `json:"tokens"`
+}
+
+// WriteJSONL writes one Record per passage to w, one JSON object per line
+func WriteJSONL(w io.Writer, passages []Passage, source string) error {
+	enc := json.NewEncoder(w)
+	for _, p := range passages {
+		rec := Record{
+			ID:   p.ID,
+			Text: p.Text,
+			Metadata: RecordMetadata{
+				Source:   source,
+				ChunkIDs: p.ChunkIDs,
+				Pages:    p.Pages,
+				Boxes:    p.Boxes,
+				Tokens:   p.Tokens,
+			},
+		}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to write passage %s: %w", p.ID, err)
+		}
+	}
+	return nil
+}
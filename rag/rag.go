@@ -0,0 +1,279 @@
+// Package rag merges parsed document chunks into token-bounded passages for
+// retrieval-augmented generation and exports them for embedding pipelines.
+package rag
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+const (
+	// DefaultMaxTokens is the default passage size
+	DefaultMaxTokens = 512
+	// captionMaxTokens is the largest text chunk still treated as a table title
+	captionMaxTokens = 40
+)
+
+var headingRe = regexp.MustCompile(`(?m)^#{1,6}\s`)
+
+// TokenCounter counts the tokens in a piece of text.
+// Counts must be additive over whitespace-separated words.
+type TokenCounter func(text string) int
+
+// EstimateTokens approximates token counts at one token per four characters,
+// with at least one token per word
+func EstimateTokens(text string) int {
+	n := 0
+	for _, word := range strings.Fields(text) {
+		n += max(1, (len(word)+3)/4)
+	}
+	return n
+}
+
+// Options configures Chunk
+type Options struct {
+	// MaxTokens is the target passage size (default DefaultMaxTokens).
+	// A table with its title is never split, so it may exceed MaxTokens on its own.
+	MaxTokens int
+	// Overlap is the number of tokens of trailing text repeated at the start of
+	// the next passage. Tables are never repeated.
+	Overlap int
+	// CountTokens counts tokens (default EstimateTokens)
+	CountTokens TokenCounter
+	// Markdown uses chunk markdown instead of plain text for passage text
+	Markdown bool
+	// IncludeMarginalia keeps page headers, footers and page numbers
+	IncludeMarginalia bool
+	// Source identifies the document and prefixes passage IDs
+	Source string
+}
+
+// PageBox is a bounding box on a page
+type PageBox struct {
+	Page int                         `json:"page"`
+	Box  landingai.ParseGroundingBox `json:"box"`
+}
+
+// Passage is a retrieval unit made of one or more source chunks
+type Passage struct {
+	ID       string    `json:"id"`
+	Text     string    `json:"text"`
+	Tokens   int       `json:"tokens"`
+	ChunkIDs []string  `json:"chunk_ids"`
+	Pages    []int     `json:"pages"`
+	Boxes    []PageBox `json:"boxes"` // Union of source chunk boxes, one per page
+}
+
+// segment is a piece of passage text and the chunks it came from
+type segment struct {
+	text   string
+	tokens int
+	chunks []landingai.ParseChunk
+	table  bool
+	// carried marks overlap text repeated from the previous passage
+	carried bool
+}
+
+// Chunk merges the response chunks into passages of at most opts.MaxTokens tokens.
+// Tables are kept together with a preceding title or caption chunk.
+func Chunk(resp *landingai.ParseResponse, opts Options) []Passage {
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultMaxTokens
+	}
+	if opts.CountTokens == nil {
+		opts.CountTokens = EstimateTokens
+	}
+	if opts.Overlap >= opts.MaxTokens {
+		opts.Overlap = opts.MaxTokens / 2
+	}
+
+	var passages []Passage
+	var cur []segment
+	curTokens := 0
+
+	emit := func() {
+		if len(cur) == 0 {
+			return
+		}
+		passages = append(passages, newPassage(cur, curTokens, len(passages), opts))
+		cur = overlapTail(cur, opts)
+		curTokens = 0
+		for _, s := range cur {
+			curTokens += s.tokens
+		}
+	}
+
+	for _, seg := range segments(resp, opts) {
+		if curTokens+seg.tokens > opts.MaxTokens && curTokens > 0 {
+			emit()
+			// Drop the overlap if it would push the segment over the limit on its own
+			if curTokens+seg.tokens > opts.MaxTokens {
+				cur, curTokens = nil, 0
+			}
+		}
+		cur = append(cur, seg)
+		curTokens += seg.tokens
+	}
+	if len(cur) > 0 && !cur[len(cur)-1].carried {
+		passages = append(passages, newPassage(cur, curTokens, len(passages), opts))
+	}
+	return passages
+}
+
+// segments turns response chunks into atomic segments, splitting long text
+// chunks and attaching titles to tables
+func segments(resp *landingai.ParseResponse, opts Options) []segment {
+	var segs []segment
+	for _, chunk := range resp.Chunks {
+		if !opts.IncludeMarginalia && isMarginalia(resp, chunk) {
+			continue
+		}
+		text := chunkText(chunk, opts)
+		if text == "" {
+			continue
+		}
+		seg := segment{text: text, tokens: opts.CountTokens(text), chunks: []landingai.ParseChunk{chunk}}
+
+		if landingai.ChunkType(chunk.Type) == landingai.ChunkTypeTable {
+			seg.table = true
+			if n := len(segs); n > 0 && isTitle(resp, segs[n-1]) {
+				prev := segs[n-1]
+				seg.text = prev.text + "\n\n" + seg.text
+				seg.tokens += prev.tokens
+				seg.chunks = append(prev.chunks, seg.chunks...)
+				segs = segs[:n-1]
+			}
+			segs = append(segs, seg)
+			continue
+		}
+
+		if seg.tokens <= opts.MaxTokens {
+			segs = append(segs, seg)
+			continue
+		}
+		segs = append(segs, splitSegment(seg, opts)...)
+	}
+	return segs
+}
+
+// splitSegment splits an oversized text segment on word boundaries
+func splitSegment(seg segment, opts Options) []segment {
+	var parts []segment
+	var words []string
+	tokens := 0
+	for _, word := range strings.Fields(seg.text) {
+		n := opts.CountTokens(word)
+		if tokens+n > opts.MaxTokens && len(words) > 0 {
+			parts = append(parts, segment{text: strings.Join(words, " "), tokens: tokens, chunks: seg.chunks})
+			words, tokens = nil, 0
+		}
+		words = append(words, word)
+		tokens += n
+	}
+	if len(words) > 0 {
+		parts = append(parts, segment{text: strings.Join(words, " "), tokens: tokens, chunks: seg.chunks})
+	}
+	return parts
+}
+
+// overlapTail returns the trailing text of a passage to repeat in the next one
+func overlapTail(segs []segment, opts Options) []segment {
+	if opts.Overlap <= 0 {
+		return nil
+	}
+	last := segs[len(segs)-1]
+	if last.table {
+		return nil
+	}
+
+	words := strings.Fields(last.text)
+	tokens := 0
+	start := len(words)
+	for start > 0 {
+		n := opts.CountTokens(words[start-1])
+		if tokens+n > opts.Overlap {
+			break
+		}
+		tokens += n
+		start--
+	}
+	if start == len(words) {
+		return nil
+	}
+	return []segment{{text: strings.Join(words[start:], " "), tokens: tokens, chunks: last.chunks, carried: true}}
+}
+
+func newPassage(segs []segment, tokens, index int, opts Options) Passage {
+	p := Passage{Tokens: tokens}
+	if opts.Source != "" {
+		p.ID = fmt.Sprintf("%s#%d", opts.Source, index)
+	} else {
+		p.ID = fmt.Sprintf("%d", index)
+	}
+
+	texts := make([]string, 0, len(segs))
+	seen := make(map[string]bool)
+	boxes := make(map[int]landingai.ParseGroundingBox)
+	for _, s := range segs {
+		texts = append(texts, s.text)
+		for _, c := range s.chunks {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			p.ChunkIDs = append(p.ChunkIDs, c.ID)
+			page := c.Grounding.Page
+			boxes[page] = boxes[page].Union(c.Grounding.Box)
+		}
+	}
+	p.Text = strings.Join(texts, "\n\n")
+
+	for page, box := range boxes {
+		p.Pages = append(p.Pages, page)
+		p.Boxes = append(p.Boxes, PageBox{Page: page, Box: box})
+	}
+	sort.Ints(p.Pages)
+	sort.Slice(p.Boxes, func(i, j int) bool { return p.Boxes[i].Page < p.Boxes[j].Page })
+	return p
+}
+
+func chunkText(chunk landingai.ParseChunk, opts Options) string {
+	if opts.Markdown {
+		return strings.TrimSpace(chunk.Markdown)
+	}
+	return chunk.Text()
+}
+
+func isMarginalia(resp *landingai.ParseResponse, chunk landingai.ParseChunk) bool {
+	if landingai.ChunkType(chunk.Type) == landingai.ChunkTypeMarginalia {
+		return true
+	}
+	switch resp.GroundingTypeOf(chunk.ID) {
+	case landingai.GroundingTypeChunkPageHeader,
+		landingai.GroundingTypeChunkPageFooter,
+		landingai.GroundingTypeChunkPageNumber,
+		landingai.GroundingTypeChunkMarginalia:
+		return true
+	}
+	return false
+}
+
+// isTitle reports whether a segment reads as a title or caption for a following table
+func isTitle(resp *landingai.ParseResponse, seg segment) bool {
+	if seg.table || len(seg.chunks) != 1 || seg.tokens > captionMaxTokens {
+		return false
+	}
+	chunk := seg.chunks[0]
+	if resp.GroundingTypeOf(chunk.ID) == landingai.GroundingTypeChunkTitle {
+		return true
+	}
+	text := strings.TrimSpace(seg.text)
+	lower := strings.ToLower(text)
+	return headingRe.MatchString(chunk.Markdown) ||
+		strings.HasPrefix(lower, "table") ||
+		strings.HasSuffix(text, ":")
+}
@@ -0,0 +1,96 @@
+package rag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func chunk(id, typ, md string, page int, box landingai.ParseGroundingBox) landingai.ParseChunk {
+	return landingai.ParseChunk{
+		ID:        id,
+		Type:      typ,
+		Markdown:  md,
+		Grounding: landingai.ParseGrounding{Page: page, Box: box},
+	}
+}
+
+func TestChunk(t *testing.T) {
+	boxA := landingai.ParseGroundingBox{Left: 0.1, Top: 0.1, Right: 0.5, Bottom: 0.2}
+	boxB := landingai.ParseGroundingBox{Left: 0.2, Top: 0.3, Right: 0.9, Bottom: 0.4}
+	resp := &landingai.ParseResponse{
+		Chunks: []landingai.ParseChunk{
+			chunk("a", "text", "one two three four", 0, boxA),
+			chunk("b", "text", "five six seven eight", 0, boxB),
+			chunk("h", "marginalia", "Page 1", 0, boxA),
+			chunk("t", "text", "Table 1: Totals", 1, boxA),
+			chunk("tbl", "table", "| k | v |\n|---|---|\n| x | 1 |", 1, boxB),
+		},
+	}
+
+	passages := Chunk(resp, Options{MaxTokens: 12, Overlap: 2, Source: "doc"})
+	if len(passages) != 2 {
+		t.Fatalf("Chunk() returned %d passages, want 2: %+v", len(passages), passages)
+	}
+
+	first := passages[0]
+	if first.ID != "doc#0" {
+		t.Errorf("ID = %q, want doc#0", first.ID)
+	}
+	if got := strings.Join(first.ChunkIDs, ","); got != "a,b" {
+		t.Errorf("ChunkIDs = %s, want a,b", got)
+	}
+	wantBox := landingai.ParseGroundingBox{Left: 0.1, Top: 0.1, Right: 0.9, Bottom: 0.4}
+	if len(first.Boxes) != 1 || first.Boxes[0].Box != wantBox {
+		t.Errorf("Boxes = %+v, want union %+v", first.Boxes, wantBox)
+	}
+
+	// Overlap carries the tail of the previous passage, and the table keeps its title
+	table := passages[1]
+	if !strings.HasPrefix(table.Text, "eight\n\nTable 1: Totals") {
+		t.Errorf("passage 1 text = %q, want overlap and title prefix", table.Text)
+	}
+	if got := strings.Join(table.ChunkIDs, ","); got != "b,t,tbl" {
+		t.Errorf("table ChunkIDs = %s, want b,t,tbl", got)
+	}
+	if got := table.Pages; len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("table Pages = %v, want [0 1]", got)
+	}
+	for _, p := range passages {
+		if strings.Contains(p.Text, "Page 1") {
+			t.Errorf("passage %s contains marginalia", p.ID)
+		}
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	passages := []Passage{
+		{ID: "doc#0", Text: "hello", ChunkIDs: []string{"a"}, Pages: []int{0}},
+		{ID: "doc#1", Text: "world", ChunkIDs: []string{"b"}, Pages: []int{1}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, passages, "doc.pdf"); err != nil {
+		t.Fatalf("WriteJSONL() error = %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	n := 0
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", n, err)
+		}
+		if rec.ID != passages[n].ID || rec.Metadata.Source != "doc.pdf" {
+			t.Errorf("line %d = %+v", n, rec)
+		}
+		n++
+	}
+	if n != len(passages) {
+		t.Errorf("wrote %d lines, want %d", n, len(passages))
+	}
+}
@@ -0,0 +1,95 @@
+package recorder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// cassetteVersion is the current cassette format version
+const cassetteVersion = 1
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest describes a recorded request. Only the fingerprint is used
+// for matching; the other fields help when reading a cassette.
+type RecordedRequest struct {
+	Fingerprint Fingerprint `json:"fingerprint"`
+	URL         string      `json:"url"`
+	Header      http.Header `json:"header,omitempty"`
+}
+
+// RecordedResponse is a recorded response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	// Body is the response body; base64-encoded when BodyEncoding is "base64"
+	Body         string `json:"body"`
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// setBody stores a body as text when possible, base64 otherwise
+func (r *RecordedResponse) setBody(body []byte) {
+	if utf8.Valid(body) {
+		r.Body, r.BodyEncoding = string(body), ""
+		return
+	}
+	r.Body, r.BodyEncoding = base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// body returns the decoded response body
+func (r *RecordedResponse) body() ([]byte, error) {
+	if r.BodyEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d", path, c.Version)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating parent directories
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
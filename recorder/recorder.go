@@ -0,0 +1,291 @@
+// Package recorder records Landing AI API interactions to cassette files and
+// replays them, so tests run deterministically without network access.
+//
+// Record once against the real API, commit the cassette, and replay in CI:
+//
+//	rec, err := recorder.New("testdata/invoice.json", recorder.ModeAuto)
+//	client := landingai.NewClient(apiKey, landingai.WithHTTPClient(rec.Client()))
+//
+// Requests are matched by fingerprint: method, path, multipart field names and
+// a hash of the content. The Authorization header is never written to disk.
+package recorder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether the recorder talks to the network
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never uses the network
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records a new cassette, replacing any existing one
+	ModeRecord
+	// ModeAuto replays if the cassette exists and records otherwise
+	ModeAuto
+)
+
+// ErrNoInteraction is returned in replay mode when the cassette has no
+// (more) recorded responses for a request
+var ErrNoInteraction = errors.New("recorder: no recorded interaction")
+
+// Redacted replaces the values of redacted headers in cassettes
+const Redacted = "REDACTED"
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithTransport sets the transport used in record mode (default http.DefaultTransport)
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithRedactedHeaders redacts additional request and response headers.
+// Authorization is always redacted.
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redact[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+	redact    map[string]bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[int]bool // replayed interaction indexes
+}
+
+// New creates a recorder for the cassette at path
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		transport: http.DefaultTransport,
+		redact:    map[string]bool{"Authorization": true},
+		used:      make(map[int]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeAuto {
+		mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			mode = ModeRecord
+		}
+	}
+	if mode == ModeRecord {
+		r.recording = true
+		r.cassette = &Cassette{Version: cassetteVersion}
+		return r, nil
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	r.cassette = cassette
+	return r, nil
+}
+
+// Client returns an HTTP client using the recorder, for landingai.WithHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Recording reports whether the recorder sends requests to the network
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := NewFingerprint(req.Method, req.URL.Path, req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.recording {
+		return r.replay(req, fp)
+	}
+	return r.record(req, fp, body)
+}
+
+// replay returns the next unused interaction matching the fingerprint
+func (r *Recorder) replay(req *http.Request, fp Fingerprint) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !in.Request.Fingerprint.Equal(fp) {
+			continue
+		}
+		r.used[i] = true
+		body, err := in.Response.body()
+		if err != nil {
+			return nil, fmt.Errorf("recorder: invalid body in cassette %s: %w", r.path, err)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s in %s", ErrNoInteraction, fp, r.path)
+}
+
+// record sends the request and appends the interaction to the cassette
+func (r *Recorder) record(req *http.Request, fp Fingerprint, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: RecordedRequest{
+			Fingerprint: fp,
+			URL:         req.URL.String(),
+			Header:      r.redactHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+		},
+	}
+	in.Response.setBody(respBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// redactHeader copies h, replacing redacted values
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if r.redact[http.CanonicalHeaderKey(name)] {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// readBody reads and closes the request body. The request itself is not
+// modified, as http.RoundTripper requires; record sends a clone carrying the body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read request body: %w", err)
+	}
+	return body, nil
+}
+
+// Fingerprint identifies a request independently of volatile details such as
+// multipart boundaries and credentials
+type Fingerprint struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Fields are the sorted multipart field names, if the body is multipart
+	Fields []string `json:"fields,omitempty"`
+	// ContentHash is a SHA-256 over the multipart fields (names, file names
+	// and contents) or over the raw body
+	ContentHash string `json:"content_hash"`
+}
+
+// NewFingerprint computes the fingerprint of a request
+func NewFingerprint(method, path, contentType string, body []byte) (Fingerprint, error) {
+	fp := Fingerprint{Method: method, Path: path}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		sum := sha256.Sum256(body)
+		fp.ContentHash = hex.EncodeToString(sum[:])
+		return fp, nil
+	}
+
+	var parts []string
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Fingerprint{}, fmt.Errorf("recorder: invalid multipart body: %w", err)
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, part); err != nil {
+			return Fingerprint{}, fmt.Errorf("recorder: invalid multipart body: %w", err)
+		}
+		fp.Fields = append(fp.Fields, part.FormName())
+		parts = append(parts, fmt.Sprintf("%s\x00%s\x00%x", part.FormName(), part.FileName(), h.Sum(nil)))
+	}
+	sort.Strings(fp.Fields)
+	sort.Strings(parts)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	fp.ContentHash = hex.EncodeToString(sum[:])
+	return fp, nil
+}
+
+// Equal reports whether two fingerprints match
+func (f Fingerprint) Equal(other Fingerprint) bool {
+	return f.Method == other.Method && f.Path == other.Path && f.ContentHash == other.ContentHash &&
+		strings.Join(f.Fields, ",") == strings.Join(other.Fields, ",")
+}
+
+// String formats the fingerprint for error messages
+func (f Fingerprint) String() string {
+	s := f.Method + " " + f.Path
+	if len(f.Fields) > 0 {
+		s += " [" + strings.Join(f.Fields, ", ") + "]"
+	}
+	return s + " " + f.ContentHash[:12]
+}
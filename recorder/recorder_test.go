@@ -0,0 +1,176 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func parse(t *testing.T, rec *Recorder, baseURL string, data string) (*landingai.ParseResponse, error) {
+	t.Helper()
+	client := landingai.NewClient("secret-api-key",
+		landingai.WithBaseURL(baseURL),
+		landingai.WithHTTPClient(rec.Client()),
+	)
+	return client.Parse(context.Background()).
+		WithFileData([]byte(data), "invoice.pdf").
+		WithModel(landingai.ModelDPT2Latest).
+		Do()
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"markdown":"# Invoice","metadata":{"page_count":1}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "invoice.json")
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !rec.Recording() {
+		t.Fatal("ModeAuto without a cassette should record")
+	}
+	if _, err := parse(t, rec, server.URL, "%PDF-1.4 invoice"); err != nil {
+		t.Fatalf("recorded Do() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-api-key") {
+		t.Error("cassette contains the API key")
+	}
+	if !strings.Contains(string(data), Redacted) {
+		t.Error("cassette does not redact Authorization")
+	}
+
+	server.Close()
+	rec, err = New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if rec.Recording() {
+		t.Fatal("ModeAuto with a cassette should replay")
+	}
+	resp, err := parse(t, rec, server.URL, "%PDF-1.4 invoice")
+	if err != nil {
+		t.Fatalf("replayed Do() error = %v", err)
+	}
+	if resp.Markdown != "# Invoice" || resp.Metadata.PageCount != 1 {
+		t.Errorf("replayed response = %+v", resp)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("server called %d times, want 1", n)
+	}
+
+	// The single recorded interaction has been used up
+	if _, err := parse(t, rec, server.URL, "%PDF-1.4 invoice"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("exhausted replay error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorder_ReplayMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(t, rec, server.URL, "original"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(t, rec, server.URL, "changed"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Do() error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorder_DoesNotModifyRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, _ := io.ReadAll(r.Body); string(data) != "payload" {
+			t.Errorf("upstream body = %q", data)
+		}
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	for _, mode := range []Mode{ModeRecord, ModeReplay} {
+		rec, err := New(path, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/ade/parse", strings.NewReader("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := req.Body
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("mode %v: RoundTrip() error = %v", mode, err)
+		}
+		resp.Body.Close()
+		if req.Body != body {
+			t.Errorf("mode %v: RoundTrip() replaced the caller's request body", mode)
+		}
+	}
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("New() error = nil, want missing cassette")
+	}
+}
+
+func TestNewFingerprint(t *testing.T) {
+	body := func(boundary string) []byte {
+		return []byte("--" + boundary + "\r\n" +
+			"Content-Disposition: form-data; name=\"model\"\r\n\r\ndpt-2-latest\r\n" +
+			"--" + boundary + "\r\n" +
+			"Content-Disposition: form-data; name=\"document\"; filename=\"a.pdf\"\r\n\r\n%PDF\r\n" +
+			"--" + boundary + "--\r\n")
+	}
+
+	a, err := NewFingerprint("POST", "/v1/ade/parse", "multipart/form-data; boundary=aaa", body("aaa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewFingerprint("POST", "/v1/ade/parse", "multipart/form-data; boundary=bbb", body("bbb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("fingerprints differ by boundary: %s vs %s", a, b)
+	}
+	if got := strings.Join(a.Fields, ","); got != "document,model" {
+		t.Errorf("Fields = %q", got)
+	}
+
+	c, _ := NewFingerprint("POST", "/v1/ade/parse", "application/json", []byte(`{}`))
+	if a.Equal(c) {
+		t.Error("multipart and JSON requests share a fingerprint")
+	}
+}
@@ -0,0 +1,79 @@
+package landingai
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/youssefsiam38/landingai/internal/markdown"
+)
+
+// HTMLOptions configures ParseResponse.RenderHTML
+type HTMLOptions struct {
+	// FullDocument wraps the output in a complete HTML document
+	FullDocument bool
+	// Title is the document title used when FullDocument is set
+	Title string
+	// ClassPrefix is prepended to the CSS classes of chunk wrappers (default "chunk")
+	ClassPrefix string
+	// SkipMarginalia omits marginalia chunks such as page headers and footers
+	SkipMarginalia bool
+}
+
+// RenderText renders the parsed document as plain text, built from its chunks.
+// Chunks are separated by blank lines and table rows become tab-separated lines.
+func (r *ParseResponse) RenderText() string {
+	var parts []string
+	for _, chunk := range r.Chunks {
+		if text := chunk.Text(); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// RenderHTML renders the parsed document as HTML, built from its chunks.
+// Each chunk is wrapped in a <div> carrying data-chunk-id, data-chunk-type,
+// data-page and data-box-* attributes taken from its grounding, so a viewer can
+// highlight the source region. Raw HTML in chunk markdown is never passed through.
+func (r *ParseResponse) RenderHTML(opts HTMLOptions) string {
+	prefix := opts.ClassPrefix
+	if prefix == "" {
+		prefix = "chunk"
+	}
+
+	var sb strings.Builder
+	if opts.FullDocument {
+		sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+		fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(opts.Title))
+		sb.WriteString("</head>\n<body>\n")
+	}
+
+	for _, chunk := range r.Chunks {
+		if opts.SkipMarginalia && ChunkType(chunk.Type) == ChunkTypeMarginalia {
+			continue
+		}
+		box := chunk.Grounding.Box
+		fmt.Fprintf(&sb,
+			`<div class="%s %s-%s" data-chunk-id="%s" data-chunk-type="%s" data-page="%d" data-box-left="%g" data-box-top="%g" data-box-right="%g" data-box-bottom="%g">`+"\n",
+			html.EscapeString(prefix),
+			html.EscapeString(prefix), html.EscapeString(chunk.Type),
+			html.EscapeString(chunk.ID),
+			html.EscapeString(chunk.Type),
+			chunk.Grounding.Page,
+			box.Left, box.Top, box.Right, box.Bottom,
+		)
+		sb.WriteString(markdown.ToHTML(chunk.Markdown))
+		sb.WriteString("</div>\n")
+	}
+
+	if opts.FullDocument {
+		sb.WriteString("</body>\n</html>\n")
+	}
+	return sb.String()
+}
+
+// Text returns the chunk content as plain text
+func (c ParseChunk) Text() string {
+	return markdown.ToText(c.Markdown)
+}
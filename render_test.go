@@ -0,0 +1,67 @@
+package landingai
+
+import (
+	"strings"
+	"testing"
+)
+
+func testParseResponse() *ParseResponse {
+	return &ParseResponse{
+		Chunks: []ParseChunk{
+			{
+				ID:       "c1",
+				Type:     "text",
+				Markdown: "<a id='c1'></a>\n\n# Invoice **#42**\n\nBilled to [ACME](https://acme.example).",
+				Grounding: ParseGrounding{
+					Page: 0,
+					Box:  ParseGroundingBox{Left: 0.1, Top: 0.1, Right: 0.9, Bottom: 0.2},
+				},
+			},
+			{
+				ID:       "c2",
+				Type:     "table",
+				Markdown: "<a id='c2'></a>\n\n<table><tr><th>Item</th><th>Qty</th></tr><tr><td>Widget &amp; Co</td><td>3</td></tr></table>",
+				Grounding: ParseGrounding{
+					Page: 1,
+					Box:  ParseGroundingBox{Left: 0.1, Top: 0.3, Right: 0.9, Bottom: 0.5},
+				},
+			},
+			{
+				ID:       "c3",
+				Type:     "marginalia",
+				Markdown: "Page 1 of 2",
+			},
+		},
+	}
+}
+
+func TestParseResponse_RenderText(t *testing.T) {
+	got := testParseResponse().RenderText()
+	want := "Invoice #42\n\nBilled to ACME.\n\nItem\tQty\nWidget & Co\t3\n\nPage 1 of 2"
+	if got != want {
+		t.Errorf("RenderText() = %q, want %q", got, want)
+	}
+}
+
+func TestParseResponse_RenderHTML(t *testing.T) {
+	got := testParseResponse().RenderHTML(HTMLOptions{SkipMarginalia: true})
+
+	for _, want := range []string{
+		`data-chunk-id="c1"`,
+		`data-page="1"`,
+		`data-box-bottom="0.5"`,
+		`<h1>Invoice <strong>#42</strong></h1>`,
+		`<a href="https://acme.example">ACME</a>`,
+		`<td>Widget &amp; Co</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderHTML() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Page 1 of 2") {
+		t.Error("RenderHTML() included marginalia with SkipMarginalia set")
+	}
+	if strings.Contains(got, "<a id=") {
+		t.Error("RenderHTML() passed through raw chunk anchors")
+	}
+}
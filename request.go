@@ -0,0 +1,143 @@
+package landingai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+)
+
+// ParseRequest is a complete parse request as a plain value, so it can be
+// queued, logged, persisted as JSON and replayed. Set exactly one of
+// DocumentURL, FilePath, Document, SourceURI or Source.
+type ParseRequest struct {
+	// DocumentURL is fetched by the API, see WithURL
+	DocumentURL *string `json:"document_url,omitempty"`
+	// FilePath is read from the local filesystem and uploaded, see WithFile
+	FilePath string `json:"file_path,omitempty"`
+	// Document is uploaded under DocumentName, see WithFileData.
+	// It is encoded as base64 in JSON.
+	Document     []byte `json:"document,omitempty"`
+	DocumentName string `json:"document_name,omitempty"`
+	// SourceURI is fetched locally and uploaded, see WithSourceURI
+	SourceURI string `json:"source_uri,omitempty"`
+	// Source supplies the document in code, see WithSource. It is not serialized.
+	Source DocumentSource `json:"-"`
+
+	ParseOptions
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Header holds extra request headers, see WithHeader
+	Header http.Header `json:"header,omitempty"`
+	// Tags label the request in logs and metrics, see WithTags
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Clone returns a copy of the request with its own options, headers and tags.
+// Document bytes and Source are shared.
+func (r ParseRequest) Clone() ParseRequest {
+	if r.DocumentURL != nil {
+		url := *r.DocumentURL
+		r.DocumentURL = &url
+	}
+	r.ParseOptions = r.ParseOptions.Clone()
+	r.Header = r.Header.Clone()
+	r.Tags = maps.Clone(r.Tags)
+	return r
+}
+
+// Validate checks that the request names exactly one document, and that
+// in-memory documents are not empty
+func (r ParseRequest) Validate() error {
+	sources := 0
+	for _, set := range []bool{r.FilePath != "", r.Document != nil, r.SourceURI != "", r.Source != nil} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case r.DocumentURL != nil && sources > 0:
+		return fmt.Errorf("cannot provide both document URL and file")
+	case r.DocumentURL == nil && sources == 0:
+		return fmt.Errorf("must provide either document URL or file")
+	case sources > 1:
+		return fmt.Errorf("cannot provide more than one document source")
+	case r.Document != nil && len(r.Document) == 0:
+		return fmt.Errorf("document is empty")
+	}
+	if src, ok := r.Source.(BytesSource); ok && len(src.Data) == 0 {
+		return fmt.Errorf("document is empty")
+	}
+	return nil
+}
+
+// source resolves the document to upload and the builder method that sets it,
+// or a nil source for URL requests
+func (r ParseRequest) source(c *Client) (DocumentSource, string) {
+	switch {
+	case r.Source != nil:
+		if src, ok := r.Source.(HTTPSource); ok && src.Client == nil {
+			src.Client = c.httpClient
+			return src, "WithSource"
+		}
+		return r.Source, "WithSource"
+	case r.FilePath != "":
+		return FileSource(r.FilePath), "WithFile"
+	case r.Document != nil:
+		return BytesSource{Data: r.Document, Name: r.DocumentName}, "WithFileData"
+	case r.SourceURI != "":
+		return uriSource{client: c, uri: r.SourceURI}, "WithSourceURI"
+	}
+	return nil, ""
+}
+
+// LogValue implements slog.LogValuer, summarizing the request without document content
+func (r ParseRequest) LogValue() slog.Value {
+	var attrs []slog.Attr
+	switch {
+	case r.DocumentURL != nil:
+		attrs = append(attrs, slog.String("document_url", *r.DocumentURL))
+	case r.FilePath != "":
+		attrs = append(attrs, slog.String("file_path", r.FilePath))
+	case r.Document != nil:
+		attrs = append(attrs, slog.String("document_name", r.DocumentName), slog.Int("document_bytes", len(r.Document)))
+	case r.SourceURI != "":
+		attrs = append(attrs, slog.String("source_uri", r.SourceURI))
+	case r.Source != nil:
+		attrs = append(attrs, slog.String("source", fmt.Sprintf("%T", r.Source)))
+	}
+	if r.Model != nil {
+		attrs = append(attrs, slog.String("model", string(*r.Model)))
+	}
+	if r.Split != nil {
+		attrs = append(attrs, slog.String("split", string(*r.Split)))
+	}
+	if r.IdempotencyKey != "" {
+		attrs = append(attrs, slog.String("idempotency_key", r.IdempotencyKey))
+	}
+	if len(r.Tags) > 0 {
+		attrs = append(attrs, tagsAttr(r.Tags))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ParseRequest executes a request built as a value. It is equivalent to
+// c.Parse(ctx).WithRequest(req).Do().
+func (c *Client) ParseRequest(ctx context.Context, req ParseRequest) (*ParseResponse, error) {
+	return c.Parse(ctx).WithRequest(req).Do()
+}
+
+// WithRequest replaces everything set on the builder so far with req
+func (b *ParseRequestBuilder) WithRequest(req ParseRequest) *ParseRequestBuilder {
+	b.req = req.Clone()
+	return b
+}
+
+// Build validates the request and returns it as a value
+func (b *ParseRequestBuilder) Build() (ParseRequest, error) {
+	if err := b.req.Validate(); err != nil {
+		return ParseRequest{}, err
+	}
+	return b.req.Clone(), nil
+}
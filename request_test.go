@@ -0,0 +1,133 @@
+package landingai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestBuilder_Build(t *testing.T) {
+	client := NewClient("test-api-key")
+	req, err := client.Parse(context.Background()).
+		WithFileData([]byte("%PDF"), "invoice.pdf").
+		WithModel(ModelDPT2Latest).
+		WithPageSplit().
+		RequireChunkTypes(ChunkTypeTable).
+		WithIdempotencyKey("inv-1").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if string(req.Document) != "%PDF" || req.DocumentName != "invoice.pdf" {
+		t.Errorf("document = %q %q", req.Document, req.DocumentName)
+	}
+	if *req.Model != ModelDPT2Latest || *req.Split != SplitTypePage || req.IdempotencyKey != "inv-1" {
+		t.Errorf("request = %+v", req)
+	}
+
+	// The last document source set wins
+	req, err = client.Parse(context.Background()).WithFile("a.pdf").WithSourceURI("s3://bucket/b.pdf").Build()
+	if err != nil || req.FilePath != "" || req.SourceURI != "s3://bucket/b.pdf" {
+		t.Errorf("Build() = %+v, %v", req, err)
+	}
+
+	if _, err := client.Parse(context.Background()).WithModel(ModelDPT2Latest).Build(); err == nil {
+		t.Error("Build() without a document succeeded")
+	}
+	// An empty document would not survive a JSON round trip, so it is rejected up front
+	if _, err := client.Parse(context.Background()).WithFileData(nil, "empty.pdf").Build(); err == nil {
+		t.Error("Build() with an empty document succeeded")
+	}
+}
+
+func TestParseRequest_Validate(t *testing.T) {
+	url := "https://example.com/doc.pdf"
+	tests := []struct {
+		name string
+		req  ParseRequest
+		want string
+	}{
+		{name: "url", req: ParseRequest{DocumentURL: &url}},
+		{name: "file", req: ParseRequest{FilePath: "doc.pdf"}},
+		{name: "none", req: ParseRequest{}, want: "must provide either"},
+		{name: "url and file", req: ParseRequest{DocumentURL: &url, FilePath: "doc.pdf"}, want: "cannot provide both"},
+		{name: "two sources", req: ParseRequest{FilePath: "doc.pdf", SourceURI: "s3://b/doc.pdf"}, want: "more than one"},
+		{name: "empty document", req: ParseRequest{Document: []byte{}, DocumentName: "doc.pdf"}, want: "document is empty"},
+		{name: "empty bytes source", req: ParseRequest{Source: BytesSource{Name: "doc.pdf"}}, want: "document is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (tt.want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ParseRequest(t *testing.T) {
+	var name, content, model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		name, content, model = header.Filename, string(data), r.FormValue("model")
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	// Requests survive a JSON round trip, e.g. through a queue
+	original, err := client.Parse(context.Background()).
+		WithFileData([]byte("queued document"), "queued.pdf").
+		WithModel(ModelDPT2MiniLatest).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed ParseRequest
+	if err := json.Unmarshal(data, &replayed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ParseRequest(context.Background(), replayed); err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if name != "queued.pdf" || content != "queued document" || model != string(ModelDPT2MiniLatest) {
+		t.Errorf("uploaded %q = %q with model %q", name, content, model)
+	}
+}
+
+func TestParseRequest_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	model := ModelDPT2Latest
+	logger.Info("parse", "request", ParseRequest{
+		Document:     []byte("secret contents"),
+		DocumentName: "doc.pdf",
+		ParseOptions: ParseOptions{Model: &model},
+	})
+
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Errorf("log contains document content: %s", out)
+	}
+	for _, want := range []string{"request.document_name=doc.pdf", "request.document_bytes=15", "request.model=dpt-2-latest"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log = %s, want %s", out, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package landingai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the response header carrying the API request ID
+const RequestIDHeader = "X-Request-Id"
+
+// ResponseInfo describes the HTTP exchange that produced a response
+type ResponseInfo struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the API request ID, useful when contacting support
+	RequestID string
+	// Latency is the time from sending the request to receiving the response headers
+	Latency time.Duration
+	// Tags are the tags set on the request with WithTags
+	Tags map[string]string
+}
+
+// newResponseInfo captures the metadata of an HTTP response
+func newResponseInfo(resp *http.Response, latency time.Duration) *ResponseInfo {
+	return &ResponseInfo{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		RequestID:  resp.Header.Get(RequestIDHeader),
+		Latency:    latency,
+	}
+}
+
+// Raw returns the raw JSON body the response was decoded from, or nil if the
+// response was not returned by Do (e.g. loaded with LoadResult).
+// The returned slice must not be modified.
+func (r *ParseResponse) Raw() []byte {
+	return r.raw
+}
+
+// HTTP returns the status, headers, request ID and latency of the HTTP
+// response, or nil if the response was not returned by Do
+func (r *ParseResponse) HTTP() *ResponseInfo {
+	return r.http
+}
+
+// clone returns a deep copy of the response, decoding its raw body again.
+// The raw body itself is shared, as it is read-only.
+func (r *ParseResponse) clone() (*ParseResponse, error) {
+	if r.raw == nil {
+		c := *r
+		return &c, nil
+	}
+	var c ParseResponse
+	if err := json.Unmarshal(r.raw, &c); err != nil {
+		return nil, fmt.Errorf("failed to copy response: %w", err)
+	}
+	c.raw = r.raw
+	if r.http != nil {
+		info := *r.http
+		info.Header = info.Header.Clone()
+		info.Tags = maps.Clone(info.Tags)
+		c.http = &info
+	}
+	return &c, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown fields in Extra
+func (r *ParseResponse) UnmarshalJSON(data []byte) error {
+	type plain ParseResponse
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*r = ParseResponse(v)
+	r.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra fields after the known ones
+func (r ParseResponse) MarshalJSON() ([]byte, error) {
+	type plain ParseResponse
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown fields in Extra
+func (m *ParseMetadata) UnmarshalJSON(data []byte) error {
+	type plain ParseMetadata
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*m = ParseMetadata(v)
+	m.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra fields after the known ones
+func (m ParseMetadata) MarshalJSON() ([]byte, error) {
+	type plain ParseMetadata
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// borrowedJSON records the bytes of a JSON value without copying them.
+// It is only valid during the Unmarshal call that filled it.
+type borrowedJSON []byte
+
+func (b *borrowedJSON) UnmarshalJSON(data []byte) error {
+	*b = data
+	return nil
+}
+
+// unknownFields returns the top-level fields of a JSON object that have no
+// matching json tag in t, or nil if there are none
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+	var fields map[string]borrowedJSON
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(t)
+	var extra map[string]json.RawMessage
+	for name, value := range fields {
+		if known[name] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = append(json.RawMessage(nil), value...)
+	}
+	return extra, nil
+}
+
+// jsonFieldNames returns the JSON names of the exported fields of a struct type
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// marshalWithExtra marshals v, a struct, and appends the extra fields in key order
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if !known[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // drop the closing brace
+	for _, k := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package landingai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const extraResponse = `{"markdown":"# Doc","chunks":[],"splits":[],"grounding":{},` +
+	`"metadata":{"filename":"doc.pdf","page_count":1,"job_id":"job-1","version":null,"org_id":null,"duration_ms":0,"credit_usage":3,"region_hint":"us-east"},` +
+	`"confidence":{"overall":0.97},"warnings":["low resolution"]}`
+
+func TestParseResponse_RawAndHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-123")
+		w.Header().Set("X-Ratelimit-Remaining", "41")
+		w.Write([]byte(extraResponse))
+	}))
+	defer server.Close()
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	resp, err := client.Parse(context.Background()).WithURL("https://example.com/doc.pdf").Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if string(resp.Raw()) != extraResponse {
+		t.Errorf("Raw() = %s", resp.Raw())
+	}
+	info := resp.HTTP()
+	if info == nil {
+		t.Fatal("HTTP() = nil")
+	}
+	if info.StatusCode != http.StatusOK || info.RequestID != "req-123" || info.Header.Get("X-Ratelimit-Remaining") != "41" {
+		t.Errorf("HTTP() = %+v", info)
+	}
+	if info.Latency <= 0 {
+		t.Errorf("Latency = %v, want > 0", info.Latency)
+	}
+
+	if (&ParseResponse{}).Raw() != nil || (&ParseResponse{}).HTTP() != nil {
+		t.Error("Raw() and HTTP() should be nil for responses not returned by Do")
+	}
+}
+
+func TestParseResponse_Extra(t *testing.T) {
+	var resp ParseResponse
+	if err := json.Unmarshal([]byte(extraResponse), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(resp.Extra) != 2 || string(resp.Extra["confidence"]) != `{"overall":0.97}` {
+		t.Errorf("Extra = %v", resp.Extra)
+	}
+	if len(resp.Metadata.Extra) != 1 || string(resp.Metadata.Extra["region_hint"]) != `"us-east"` {
+		t.Errorf("Metadata.Extra = %v", resp.Metadata.Extra)
+	}
+	if resp.Metadata.JobID != "job-1" || resp.Metadata.CreditUsage != 3 {
+		t.Errorf("known metadata fields lost: %+v", resp.Metadata)
+	}
+
+	data, err := json.Marshal(&resp)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var got, want map[string]interface{}
+	json.Unmarshal(data, &got)
+	json.Unmarshal([]byte(extraResponse), &want)
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Errorf("round trip =\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+}
+
+func TestParseResponse_ExtraOmittedWhenEmpty(t *testing.T) {
+	var resp ParseResponse
+	if err := json.Unmarshal([]byte(`{"markdown":"x","metadata":{"page_count":1}}`), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if resp.Extra != nil || resp.Metadata.Extra != nil {
+		t.Errorf("Extra = %v, Metadata.Extra = %v, want nil", resp.Extra, resp.Metadata.Extra)
+	}
+
+	// Extra keys that shadow known fields are ignored when marshaling
+	resp.Extra = map[string]json.RawMessage{"markdown": json.RawMessage(`"shadow"`)}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var back ParseResponse
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if back.Markdown != "x" {
+		t.Errorf("Markdown = %q, want %q", back.Markdown, "x")
+	}
+}
+
+func TestSaveResult_PreservesExtra(t *testing.T) {
+	var resp ParseResponse
+	if err := json.Unmarshal([]byte(extraResponse), &resp); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := SaveResult(&buf, &resp, ResultSource{Name: "doc.pdf"}); err != nil {
+		t.Fatalf("SaveResult() error = %v", err)
+	}
+	stored, err := LoadResult(&buf)
+	if err != nil {
+		t.Fatalf("LoadResult() error = %v", err)
+	}
+	if string(stored.Response.Extra["warnings"]) != `["low resolution"]` {
+		t.Errorf("Extra after LoadResult = %v", stored.Response.Extra)
+	}
+}
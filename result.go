@@ -0,0 +1,271 @@
+package landingai
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ResultFormatVersion is the current version of the saved result envelope
+const ResultFormatVersion = 1
+
+// ResultSource describes the document and request options a result was produced from
+type ResultSource struct {
+	Name        string     `json:"name,omitempty"`
+	SHA256      string     `json:"sha256,omitempty"` // Hex-encoded SHA-256 of the document
+	Size        int64      `json:"size,omitempty"`
+	DocumentURL string     `json:"document_url,omitempty"`
+	Model       Model      `json:"model,omitempty"`
+	Split       *SplitType `json:"split,omitempty"`
+}
+
+// SourceFromBytes describes an in-memory document
+func SourceFromBytes(name string, data []byte) ResultSource {
+	sum := sha256.Sum256(data)
+	return ResultSource{
+		Name:   name,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(data)),
+	}
+}
+
+// SourceFromFile describes a document on disk, hashing its content
+func SourceFromFile(path string) (ResultSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ResultSource{}, fmt.Errorf("failed to open source: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return ResultSource{}, fmt.Errorf("failed to hash source: %w", err)
+	}
+	return ResultSource{
+		Name:   filepath.Base(path),
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+		Size:   n,
+	}, nil
+}
+
+// StoredResult is the versioned envelope written by SaveResult
+type StoredResult struct {
+	FormatVersion int    `json:"format_version"`
+	SDKVersion    string `json:"sdk_version"`
+	// ServerVersion is the version reported in the response metadata, if any.
+	// It is recorded as returned and not interpreted as a Model.
+	ServerVersion string         `json:"server_version,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Source        ResultSource   `json:"source"`
+	Response      *ParseResponse `json:"response"`
+}
+
+// Compression identifies how a saved result is compressed
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd requires a codec registered with RegisterCompressor,
+	// as the standard library has no zstd implementation
+	CompressionZstd Compression = "zstd"
+)
+
+// Compressor wraps readers and writers for a compression format
+type Compressor struct {
+	// Magic is the byte prefix LoadResult uses to detect the format
+	Magic     []byte
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[Compression]Compressor{
+		CompressionGzip: {
+			Magic: []byte{0x1f, 0x8b},
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriter(w), nil
+			},
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(r)
+			},
+		},
+	}
+	// zstdMagic lets LoadResult report a helpful error when no zstd codec is registered
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// RegisterCompressor registers a compression codec for SaveResult and LoadResult.
+// Use it to plug in zstd, e.g. backed by github.com/klauspost/compress/zstd.
+func RegisterCompressor(name Compression, c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[name] = c
+}
+
+// ResultMigration upgrades a raw envelope from one format version to the next.
+// It receives the top-level envelope fields and may rewrite any of them.
+type ResultMigration func(envelope map[string]json.RawMessage) error
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = map[int]ResultMigration{}
+)
+
+// RegisterResultMigration registers a migration from format version from to from+1.
+// LoadResult applies migrations in sequence until the envelope reaches ResultFormatVersion.
+func RegisterResultMigration(from int, m ResultMigration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	migrations[from] = m
+}
+
+// SaveOption configures SaveResult
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	compression Compression
+}
+
+// WithCompression compresses the saved result
+func WithCompression(c Compression) SaveOption {
+	return func(o *saveOptions) {
+		o.compression = c
+	}
+}
+
+// SaveResult writes the response to w in a versioned envelope recording the SDK
+// and server versions, source document, request options and a timestamp.
+// source.Model is stored as given; set it to the model the request used.
+func SaveResult(w io.Writer, resp *ParseResponse, source ResultSource, opts ...SaveOption) error {
+	var o saveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if source.Name == "" {
+		source.Name = resp.Metadata.Filename
+	}
+
+	var serverVersion string
+	if resp.Metadata.Version != nil {
+		serverVersion = *resp.Metadata.Version
+	}
+
+	envelope := StoredResult{
+		FormatVersion: ResultFormatVersion,
+		SDKVersion:    Version,
+		ServerVersion: serverVersion,
+		CreatedAt:     time.Now().UTC(),
+		Source:        source,
+		Response:      resp,
+	}
+
+	out := w
+	var closer io.Closer
+	if o.compression != CompressionNone {
+		compressorsMu.RLock()
+		c, ok := compressors[o.compression]
+		compressorsMu.RUnlock()
+		if !ok {
+			return fmt.Errorf("compression %q is not registered", o.compression)
+		}
+		cw, err := c.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("failed to create %s writer: %w", o.compression, err)
+		}
+		out, closer = cw, cw
+	}
+
+	if err := json.NewEncoder(out).Encode(envelope); err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if closer != nil {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("failed to flush %s writer: %w", o.compression, err)
+		}
+	}
+	return nil
+}
+
+// LoadResult reads a result written by SaveResult, detecting compression and
+// applying registered migrations to envelopes from older format versions
+func LoadResult(r io.Reader) (*StoredResult, error) {
+	in, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(in).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	var version int
+	if err := json.Unmarshal(raw["format_version"], &version); err != nil {
+		return nil, fmt.Errorf("result has no valid format_version: %w", err)
+	}
+	if version > ResultFormatVersion {
+		return nil, fmt.Errorf("result format version %d is newer than supported version %d", version, ResultFormatVersion)
+	}
+
+	for ; version < ResultFormatVersion; version++ {
+		migrationsMu.RLock()
+		migrate, ok := migrations[version]
+		migrationsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("no migration registered from result format version %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("failed to migrate result from format version %d: %w", version, err)
+		}
+	}
+	raw["format_version"] = json.RawMessage(fmt.Sprint(ResultFormatVersion))
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode migrated result: %w", err)
+	}
+	var result StoredResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return &result, nil
+}
+
+// decompress sniffs the stream's magic bytes and wraps it in the matching decompressor
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	for name, c := range compressors {
+		if len(c.Magic) > 0 && bytes.HasPrefix(head, c.Magic) {
+			rc, err := c.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create %s reader: %w", name, err)
+			}
+			return rc, nil
+		}
+	}
+	if bytes.HasPrefix(head, zstdMagic) {
+		return nil, fmt.Errorf("result is zstd-compressed but no zstd compressor is registered")
+	}
+	return io.NopCloser(br), nil
+}
@@ -0,0 +1,78 @@
+package landingai
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSaveResult_LoadResult(t *testing.T) {
+	version := "dpt-2-20250919"
+	resp := &ParseResponse{
+		Markdown: "# Hello",
+		Chunks:   []ParseChunk{{ID: "c1", Type: "text", Markdown: "# Hello"}},
+		Metadata: ParseMetadata{Filename: "doc.pdf", PageCount: 1, Version: &version},
+	}
+	source := SourceFromBytes("doc.pdf", []byte("%PDF-1.7"))
+
+	for _, compression := range []Compression{CompressionNone, CompressionGzip} {
+		t.Run("compression="+string(compression), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := SaveResult(&buf, resp, source, WithCompression(compression)); err != nil {
+				t.Fatalf("SaveResult() error = %v", err)
+			}
+
+			got, err := LoadResult(&buf)
+			if err != nil {
+				t.Fatalf("LoadResult() error = %v", err)
+			}
+			if got.FormatVersion != ResultFormatVersion || got.SDKVersion == "" || got.CreatedAt.IsZero() {
+				t.Errorf("envelope = %+v", got)
+			}
+			if got.Source.SHA256 != source.SHA256 || got.Source.Model != "" {
+				t.Errorf("Source = %+v, want model left unset", got.Source)
+			}
+			if got.ServerVersion != version {
+				t.Errorf("ServerVersion = %q, want %q", got.ServerVersion, version)
+			}
+			if got.Response.Markdown != resp.Markdown || len(got.Response.Chunks) != 1 {
+				t.Errorf("Response = %+v", got.Response)
+			}
+		})
+	}
+}
+
+func TestSaveResult_UnregisteredCompression(t *testing.T) {
+	err := SaveResult(&bytes.Buffer{}, &ParseResponse{}, ResultSource{}, WithCompression(CompressionZstd))
+	if err == nil {
+		t.Error("SaveResult() with unregistered zstd succeeded")
+	}
+}
+
+func TestLoadResult_Migration(t *testing.T) {
+	RegisterResultMigration(0, func(envelope map[string]json.RawMessage) error {
+		// Version 0 stored the response under "result"
+		envelope["response"] = envelope["result"]
+		delete(envelope, "result")
+		return nil
+	})
+	defer func() {
+		migrationsMu.Lock()
+		delete(migrations, 0)
+		migrationsMu.Unlock()
+	}()
+
+	old := `{"format_version":0,"sdk_version":"0.0.1","result":{"markdown":"legacy"}}`
+	got, err := LoadResult(strings.NewReader(old))
+	if err != nil {
+		t.Fatalf("LoadResult() error = %v", err)
+	}
+	if got.FormatVersion != ResultFormatVersion || got.Response == nil || got.Response.Markdown != "legacy" {
+		t.Errorf("LoadResult() = %+v", got)
+	}
+
+	if _, err := LoadResult(strings.NewReader(`{"format_version":99}`)); err == nil {
+		t.Error("LoadResult() accepted a newer format version")
+	}
+}
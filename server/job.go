@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+// JobStatus is the lifecycle state of a parse job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a queued parse request and, once finished, its outcome
+type Job struct {
+	ID       string    `json:"job_id"`
+	Tenant   string    `json:"-"`
+	Status   JobStatus `json:"status"`
+	Filename string    `json:"filename,omitempty"`
+	URL      string    `json:"document_url,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Split    string    `json:"split,omitempty"`
+	// Tags are the request tags, sent as repeated key=value tag fields
+	Tags       map[string]string `json:"tags,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	// StatusCode is the upstream status code of a failed job, if any
+	StatusCode int `json:"status_code,omitempty"`
+
+	Result *landingai.ParseResponse `json:"-"`
+
+	spool string // path of the uploaded document until the job finishes
+	err   error
+	done  chan struct{}
+}
+
+// ErrJobNotFound is returned by a Store for unknown job IDs
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrServerClosed fails jobs that were still queued when the server closed
+var ErrServerClosed = errors.New("server is shutting down")
+
+// Store persists jobs and their results. Implementations must store a
+// snapshot of the job rather than the pointer, since workers keep updating it.
+type Store interface {
+	Put(ctx context.Context, job *Job) error
+	Get(ctx context.Context, id string) (*Job, error)
+}
+
+// MemoryStore keeps jobs in memory, dropping finished jobs after a retention period
+type MemoryStore struct {
+	mu        sync.Mutex
+	jobs      map[string]*Job
+	retention time.Duration
+}
+
+// NewMemoryStore creates an in-memory store. Finished jobs are dropped after
+// retention; zero keeps them forever.
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job), retention: retention}
+}
+
+// Put implements Store. The job is copied, so callers may keep updating it.
+func (m *MemoryStore) Put(ctx context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *job
+	stored.spool = ""
+	m.jobs[job.ID] = &stored
+	m.expire()
+	return nil
+}
+
+// Get implements Store
+func (m *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// expire drops finished jobs older than the retention period
+func (m *MemoryStore) expire() {
+	if m.retention <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-m.retention)
+	for id, job := range m.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,560 @@
+// Package server exposes the SDK as an internal HTTP gateway, so services can
+// parse documents without holding Landing AI API keys.
+//
+// The gateway mirrors the upstream multipart contract on POST /parse and adds
+// asynchronous jobs:
+//
+//	POST /parse             parse synchronously (document or document_url, model, split, tag)
+//	POST /jobs              queue a parse job, returns 202 with the job
+//	GET  /jobs/{id}         job status
+//	GET  /jobs/{id}/result  parse result of a finished job
+//	GET  /usage             credits used by the calling tenant
+//
+// Callers authenticate with per-tenant bearer tokens. Requests are processed by
+// a fixed pool of workers behind a bounded queue; uploads wait on disk in
+// Config.SpoolDir. Upstream 401, 402 and 403 errors concern the gateway's own
+// API key and are answered with 503.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+const (
+	// DefaultWorkers is the default number of concurrent upstream requests
+	DefaultWorkers = 4
+	// DefaultQueueSize is the default number of jobs waiting for a worker
+	DefaultQueueSize = 100
+	// DefaultMaxUploadBytes is the default upload size limit
+	DefaultMaxUploadBytes = 100 << 20
+
+	// multipartMemory is how much of a multipart request is buffered in memory;
+	// larger uploads are written to temporary files while parsing
+	multipartMemory = 1 << 20
+)
+
+// Config configures a Server
+type Config struct {
+	// Client sends the upstream requests
+	Client *landingai.Client
+	// Tokens maps bearer tokens to tenant names
+	Tokens map[string]string
+	// Workers is the number of concurrent upstream requests (default DefaultWorkers)
+	Workers int
+	// QueueSize is the number of jobs that may wait for a worker (default DefaultQueueSize).
+	// Requests beyond it are rejected with 503.
+	QueueSize int
+	// MaxUploadBytes limits the size of uploaded documents (default DefaultMaxUploadBytes)
+	MaxUploadBytes int64
+	// SpoolDir holds uploaded documents on disk until a worker has sent them
+	// upstream (default os.TempDir()), so queued jobs do not hold uploads in memory
+	SpoolDir string
+	// Store keeps jobs and results (default an in-memory store with 24h retention)
+	Store Store
+	// Logger receives request and job logs (default slog.Default())
+	Logger *slog.Logger
+}
+
+// Server is an HTTP gateway in front of a landingai.Client
+type Server struct {
+	cfg   Config
+	queue chan *Job
+	mux   *http.ServeMux
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	// closeMu orders submissions before Close drains the queue
+	closeMu sync.RWMutex
+	closed  bool
+
+	usageMu sync.Mutex
+	usage   map[string]*Usage
+}
+
+// Usage is the upstream usage accumulated by a tenant
+type Usage struct {
+	Tenant string `json:"tenant"`
+	UsageCounts
+	// ByTag breaks usage down by request tag, keyed "key=value"
+	ByTag map[string]UsageCounts `json:"by_tag,omitempty"`
+}
+
+// UsageCounts are the counters of a Usage
+type UsageCounts struct {
+	Requests    int     `json:"requests"`
+	Failures    int     `json:"failures"`
+	Pages       int     `json:"pages"`
+	CreditUsage float64 `json:"credit_usage"`
+}
+
+// add counts one request
+func (c *UsageCounts) add(resp *landingai.ParseResponse, err error) {
+	c.Requests++
+	if err != nil {
+		c.Failures++
+		return
+	}
+	c.Pages += resp.Metadata.PageCount
+	c.CreditUsage += resp.Metadata.CreditUsage
+}
+
+// New creates a server and starts its workers. Call Close to stop them.
+func New(cfg Config) (*Server, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("server requires a client")
+	}
+	if len(cfg.Tokens) == 0 {
+		return nil, fmt.Errorf("server requires at least one tenant token")
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore(24 * time.Hour)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cfg:    cfg,
+		queue:  make(chan *Job, cfg.QueueSize),
+		mux:    http.NewServeMux(),
+		cancel: cancel,
+		usage:  make(map[string]*Usage),
+	}
+	s.mux.HandleFunc("POST /parse", s.auth(s.handleParse))
+	s.mux.HandleFunc("POST /jobs", s.auth(s.handleCreateJob))
+	s.mux.HandleFunc("GET /jobs/{id}", s.auth(s.handleGetJob))
+	s.mux.HandleFunc("GET /jobs/{id}/result", s.auth(s.handleGetResult))
+	s.mux.HandleFunc("GET /usage", s.auth(s.handleUsage))
+
+	for i := 0; i < cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker(ctx)
+	}
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close stops the workers, cancelling in-flight upstream requests, and fails
+// jobs still waiting in the queue with ErrServerClosed
+func (s *Server) Close() {
+	s.closeMu.Lock()
+	s.closed = true
+	s.closeMu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+	for {
+		select {
+		case job := <-s.queue:
+			s.finish(job, nil, ErrServerClosed)
+		default:
+			return
+		}
+	}
+}
+
+// Usage returns the accumulated usage of a tenant
+func (s *Server) Usage(tenant string) Usage {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	if u, ok := s.usage[tenant]; ok {
+		usage := *u
+		usage.ByTag = maps.Clone(u.ByTag)
+		return usage
+	}
+	return Usage{Tenant: tenant}
+}
+
+type tenantKey struct{}
+
+// auth resolves the bearer token to a tenant
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		tenant, known := s.cfg.Tokens[strings.TrimSpace(token)]
+		if !ok || !known {
+			writeError(w, http.StatusUnauthorized, "invalid or missing API token")
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenant)))
+	}
+}
+
+func tenantOf(r *http.Request) string {
+	tenant, _ := r.Context().Value(tenantKey{}).(string)
+	return tenant
+}
+
+// handleParse queues a job and waits for its result
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	job, _, ok := s.submit(w, r)
+	if !ok {
+		return
+	}
+	select {
+	case <-job.done:
+	case <-r.Context().Done():
+		return
+	}
+	if job.err != nil {
+		writeJobError(w, job)
+		return
+	}
+	writeJSON(w, http.StatusOK, job.Result)
+}
+
+// handleCreateJob queues a job and returns immediately
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	_, queued, ok := s.submit(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+queued.ID)
+	writeJSON(w, http.StatusAccepted, queued)
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleGetResult(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	switch job.Status {
+	case JobSucceeded:
+		writeJSON(w, http.StatusOK, job.Result)
+	case JobFailed:
+		writeJobError(w, job)
+	default:
+		writeError(w, http.StatusConflict, fmt.Sprintf("job is %s", job.Status))
+	}
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Usage(tenantOf(r)))
+}
+
+// lookup loads a job owned by the calling tenant
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*Job, bool) {
+	job, err := s.cfg.Store.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, ErrJobNotFound) || (err == nil && job.Tenant != tenantOf(r)) {
+		// Never reveal other tenants' jobs
+		writeError(w, http.StatusNotFound, "job not found")
+		return nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load job")
+		return nil, false
+	}
+	return job, true
+}
+
+// submit reads the multipart request, stores the job and queues it. It returns
+// the live job, owned by a worker once queued, and a snapshot taken before queueing.
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) (*Job, Job, bool) {
+	job, status, err := s.readJob(w, r)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return nil, Job{}, false
+	}
+	if err := s.cfg.Store.Put(r.Context(), job); err != nil {
+		os.Remove(job.spool)
+		writeError(w, http.StatusInternalServerError, "failed to store job")
+		return nil, Job{}, false
+	}
+
+	queued := *job
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		s.finish(job, nil, ErrServerClosed)
+		writeError(w, http.StatusServiceUnavailable, ErrServerClosed.Error())
+		return nil, Job{}, false
+	}
+	select {
+	case s.queue <- job:
+	default:
+		s.finish(job, nil, fmt.Errorf("queue is full"))
+		w.Header().Set("Retry-After", "5")
+		writeError(w, http.StatusServiceUnavailable, "queue is full, retry later")
+		return nil, Job{}, false
+	}
+	s.cfg.Logger.Info("landingai: job queued", "job", queued.ID, "tenant", queued.Tenant, "filename", queued.Filename)
+	return job, queued, true
+}
+
+// readJob builds a job from the upstream multipart contract:
+// document or document_url, plus optional model and split fields
+func (s *Server) readJob(w http.ResponseWriter, r *http.Request) (*Job, int, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadBytes)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("document exceeds %d bytes", s.cfg.MaxUploadBytes)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid multipart form: %v", err)
+	}
+
+	tags, err := parseTags(r.MultipartForm.Value["tag"])
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	job := &Job{
+		ID:        newJobID(),
+		Tenant:    tenantOf(r),
+		Status:    JobQueued,
+		URL:       r.FormValue("document_url"),
+		Model:     r.FormValue("model"),
+		Split:     r.FormValue("split"),
+		Tags:      tags,
+		CreatedAt: time.Now().UTC(),
+		done:      make(chan struct{}),
+	}
+
+	file, header, err := r.FormFile("document")
+	switch {
+	case err == nil:
+		defer file.Close()
+		if job.URL != "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("cannot provide both document and document_url")
+		}
+		job.spool, err = s.spool(file)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		job.Filename = header.Filename
+	case errors.Is(err, http.ErrMissingFile):
+		if job.URL == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("must provide either document or document_url")
+		}
+	default:
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid document: %v", err)
+	}
+	return job, 0, nil
+}
+
+// parseTags parses repeated tag fields of the form key=value
+func parseTags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	tags := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, want key=value", v)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// spool copies an uploaded document to a file in SpoolDir
+func (s *Server) spool(file io.Reader) (string, error) {
+	f, err := os.CreateTemp(s.cfg.SpoolDir, "landingai-upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to store document: %v", err)
+	}
+	_, err = io.Copy(f, file)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to store document: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// spooledDocument is a landingai.DocumentSource for a spooled upload
+type spooledDocument struct {
+	path, name string
+}
+
+// Open implements landingai.DocumentSource
+func (d spooledDocument) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	f, err := os.Open(d.path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read spooled document: %w", err)
+	}
+	return f, d.name, nil
+}
+
+// worker processes queued jobs until ctx is cancelled
+func (s *Server) worker(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// run sends one job upstream
+func (s *Server) run(ctx context.Context, job *Job) {
+	started := time.Now().UTC()
+	job.StartedAt = &started
+	job.Status = JobRunning
+	s.cfg.Store.Put(ctx, job)
+
+	builder := s.cfg.Client.Parse(ctx)
+	if job.URL != "" {
+		builder.WithURL(job.URL)
+	} else {
+		builder.WithSource(spooledDocument{path: job.spool, name: job.Filename})
+	}
+	if job.Model != "" {
+		builder.WithModel(landingai.Model(job.Model))
+	}
+	if job.Split != "" {
+		builder.WithSplit(landingai.SplitType(job.Split))
+	}
+
+	builder.WithTags(job.Tags)
+
+	resp, err := builder.Do()
+	s.finish(job, resp, err)
+	s.record(job.Tenant, job.Tags, resp, err)
+
+	logger := s.cfg.Logger.With("job", job.ID, "tenant", job.Tenant, "tags", job.Tags, "duration", time.Since(started))
+	if err != nil {
+		logger.Error("landingai: job failed", "error", err)
+	} else {
+		logger.Info("landingai: job succeeded", "pages", resp.Metadata.PageCount, "credits", resp.Metadata.CreditUsage)
+	}
+}
+
+// finish records the outcome of a job and wakes up waiting requests
+func (s *Server) finish(job *Job, resp *landingai.ParseResponse, err error) {
+	finished := time.Now().UTC()
+	job.FinishedAt = &finished
+	if job.spool != "" {
+		os.Remove(job.spool)
+		job.spool = ""
+	}
+	job.Result, job.err = resp, err
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		var apiErr *landingai.APIError
+		if errors.As(err, &apiErr) {
+			job.StatusCode = apiErr.StatusCode
+			if isGatewayFault(apiErr) {
+				// The gateway's own credentials or credits are not the tenant's business
+				job.StatusCode = http.StatusServiceUnavailable
+				job.err = errUpstreamUnavailable
+				job.Error = errUpstreamUnavailable.Error()
+			}
+		}
+		var valErr *landingai.ValidationErrors
+		if errors.As(err, &valErr) {
+			job.StatusCode = landingai.StatusUnprocessableEntity
+		}
+		if errors.Is(err, ErrServerClosed) {
+			job.StatusCode = http.StatusServiceUnavailable
+		}
+	} else {
+		job.Status = JobSucceeded
+	}
+	s.cfg.Store.Put(context.Background(), job)
+	close(job.done)
+}
+
+// errUpstreamUnavailable replaces upstream errors caused by the gateway's own account
+var errUpstreamUnavailable = errors.New("upstream service unavailable")
+
+// isGatewayFault reports whether an upstream error concerns the gateway's API
+// key or credits rather than the tenant's request
+func isGatewayFault(err *landingai.APIError) bool {
+	return err.IsUnauthorized() || err.IsPaymentRequired() || err.StatusCode == http.StatusForbidden
+}
+
+// record accumulates the tenant's usage, in total and per tag
+func (s *Server) record(tenant string, tags map[string]string, resp *landingai.ParseResponse, err error) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	u, ok := s.usage[tenant]
+	if !ok {
+		u = &Usage{Tenant: tenant}
+		s.usage[tenant] = u
+	}
+	u.add(resp, err)
+	for k, v := range tags {
+		if u.ByTag == nil {
+			u.ByTag = make(map[string]UsageCounts)
+		}
+		counts := u.ByTag[k+"="+v]
+		counts.add(resp, err)
+		u.ByTag[k+"="+v] = counts
+	}
+}
+
+// writeJobError writes a failed job's error, keeping the upstream status code
+// for API errors about the request and answering 502 for other upstream failures.
+// Upstream 401, 402 and 403 concern the gateway's own account and were
+// replaced with 503 by finish.
+func writeJobError(w http.ResponseWriter, job *Job) {
+	status := job.StatusCode
+	if status == 0 {
+		status = http.StatusBadGateway
+	}
+	var valErr *landingai.ValidationErrors
+	if errors.As(job.err, &valErr) {
+		writeJSON(w, status, valErr)
+		return
+	}
+	var apiErr *landingai.APIError
+	if errors.As(job.err, &apiErr) && apiErr.Detail != nil {
+		writeJSON(w, status, map[string]interface{}{"detail": apiErr.Detail})
+		return
+	}
+	writeError(w, status, job.Error)
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,384 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func newTestServer(t *testing.T, upstream http.HandlerFunc, cfg Config) *httptest.Server {
+	t.Helper()
+	api := httptest.NewServer(upstream)
+	t.Cleanup(api.Close)
+
+	cfg.Client = landingai.NewClient("upstream-key", landingai.WithBaseURL(api.URL))
+	if cfg.Tokens == nil {
+		cfg.Tokens = map[string]string{"token-a": "tenant-a", "token-b": "tenant-b"}
+	}
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+	gateway := httptest.NewServer(srv)
+	t.Cleanup(gateway.Close)
+	return gateway
+}
+
+func multipartBody(t *testing.T, fields map[string]string, filename string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if filename != "" {
+		part, err := writer.CreateFormFile("document", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("%PDF-1.4"))
+	}
+	for k, v := range fields {
+		writer.WriteField(k, v)
+	}
+	writer.Close()
+	return &buf, writer.FormDataContentType()
+}
+
+func do(t *testing.T, method, url, token string, body io.Reader, contentType string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServer_Parse(t *testing.T) {
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer upstream-key" {
+			t.Errorf("upstream Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if r.FormValue("model") != "dpt-2-latest" {
+			t.Errorf("upstream model = %q", r.FormValue("model"))
+		}
+		if _, header, err := r.FormFile("document"); err != nil || header.Filename != "invoice.pdf" {
+			t.Errorf("upstream document = %v, %v", header, err)
+		}
+		w.Write([]byte(`{"markdown":"# Invoice","metadata":{"page_count":2,"credit_usage":6}}`))
+	}, Config{})
+
+	body, ct := multipartBody(t, map[string]string{"model": "dpt-2-latest"}, "invoice.pdf")
+	resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var result landingai.ParseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Markdown != "# Invoice" {
+		t.Errorf("Markdown = %q", result.Markdown)
+	}
+
+	var usage Usage
+	json.NewDecoder(do(t, http.MethodGet, gateway.URL+"/usage", "token-a", nil, "").Body).Decode(&usage)
+	if usage.Tenant != "tenant-a" || usage.Requests != 1 || usage.Pages != 2 || usage.CreditUsage != 6 {
+		t.Errorf("usage = %+v", usage)
+	}
+	json.NewDecoder(do(t, http.MethodGet, gateway.URL+"/usage", "token-b", nil, "").Body).Decode(&usage)
+	if usage.Tenant != "tenant-b" || usage.Requests != 0 {
+		t.Errorf("other tenant usage = %+v", usage)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("upstream called without a valid token")
+	}, Config{})
+
+	for _, token := range []string{"", "wrong"} {
+		body, ct := multipartBody(t, nil, "doc.pdf")
+		resp := do(t, http.MethodPost, gateway.URL+"/parse", token, body, ct)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, resp.StatusCode)
+		}
+	}
+}
+
+func TestServer_Validation(t *testing.T) {
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("upstream called for an invalid request")
+	}, Config{})
+
+	tests := []struct {
+		name     string
+		fields   map[string]string
+		filename string
+	}{
+		{name: "no document", fields: map[string]string{"model": "dpt-2-latest"}},
+		{name: "document and url", fields: map[string]string{"document_url": "https://example.com/a.pdf"}, filename: "a.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, ct := multipartBody(t, tt.fields, tt.filename)
+			resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_UpstreamError(t *testing.T) {
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"detail":"Unsupported file type"}`))
+	}, Config{})
+
+	body, ct := multipartBody(t, nil, "doc.pdf")
+	resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", resp.StatusCode)
+	}
+	var detail struct{ Detail string }
+	json.NewDecoder(resp.Body).Decode(&detail)
+	if detail.Detail != "Unsupported file type" {
+		t.Errorf("detail = %q", detail.Detail)
+	}
+}
+
+func TestServer_UpstreamAccountErrorsHidden(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		detail string
+	}{
+		{http.StatusUnauthorized, "Invalid API key"},
+		{http.StatusPaymentRequired, "Insufficient credits"},
+	} {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"detail":"` + tt.detail + `"}`))
+			}, Config{})
+
+			body, ct := multipartBody(t, nil, "doc.pdf")
+			resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct)
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want 503", resp.StatusCode)
+			}
+			data, _ := io.ReadAll(resp.Body)
+			if strings.Contains(string(data), tt.detail) {
+				t.Errorf("body = %s, want upstream detail hidden", data)
+			}
+		})
+	}
+}
+
+func TestServer_SpoolsUploads(t *testing.T) {
+	spool := t.TempDir()
+	var spooled []os.DirEntry
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		spooled, _ = os.ReadDir(spool)
+		if _, header, err := r.FormFile("document"); err != nil || header.Filename != "doc.pdf" {
+			t.Errorf("upstream document = %v, %v", header, err)
+		}
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}, Config{SpoolDir: spool})
+
+	body, ct := multipartBody(t, nil, "doc.pdf")
+	if resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(spooled) != 1 {
+		t.Errorf("spooled files during upload = %d, want 1", len(spooled))
+	}
+	if left, _ := os.ReadDir(spool); len(left) != 0 {
+		t.Errorf("spooled files after the job = %d, want 0", len(left))
+	}
+}
+
+func TestServer_CloseFailsQueuedJobs(t *testing.T) {
+	release := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"markdown":""}`))
+	}))
+	t.Cleanup(api.Close)
+	srv, err := New(Config{
+		Client:   landingai.NewClient("upstream-key", landingai.WithBaseURL(api.URL)),
+		Tokens:   map[string]string{"token-a": "tenant-a"},
+		Workers:  1,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		SpoolDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := httptest.NewServer(srv)
+	t.Cleanup(gateway.Close)
+	// Registered last so it runs before the servers shut down
+	t.Cleanup(func() { close(release) })
+
+	// The first job occupies the worker, the second waits in the queue
+	statuses := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			body, ct := multipartBody(t, nil, "doc.pdf")
+			req, _ := http.NewRequest(http.MethodPost, gateway.URL+"/parse", body)
+			req.Header.Set("Authorization", "Bearer token-a")
+			req.Header.Set("Content-Type", ct)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		srv.Close()
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case status := <-statuses:
+			if status != http.StatusServiceUnavailable && status != http.StatusBadGateway {
+				t.Errorf("status = %d, want the job failed by Close", status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("request still waiting after Close")
+		}
+	}
+	<-done
+
+	body, ct := multipartBody(t, nil, "doc.pdf")
+	if resp := do(t, http.MethodPost, gateway.URL+"/jobs", "token-a", body, ct); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("submit after Close: status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestServer_Jobs(t *testing.T) {
+	release := make(chan struct{})
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"markdown":"# Async","metadata":{"page_count":1,"credit_usage":3}}`))
+	}, Config{})
+
+	body, ct := multipartBody(t, map[string]string{"document_url": "https://example.com/a.pdf"}, "")
+	resp := do(t, http.MethodPost, gateway.URL+"/jobs", "token-a", body, ct)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", resp.StatusCode)
+	}
+	var job Job
+	json.NewDecoder(resp.Body).Decode(&job)
+	if job.ID == "" || job.Status != JobQueued {
+		t.Fatalf("job = %+v", job)
+	}
+	if got := resp.Header.Get("Location"); got != "/jobs/"+job.ID {
+		t.Errorf("Location = %q", got)
+	}
+
+	if resp := do(t, http.MethodGet, gateway.URL+"/jobs/"+job.ID+"/result", "token-a", nil, ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("result before completion: status = %d, want 409", resp.StatusCode)
+	}
+	if resp := do(t, http.MethodGet, gateway.URL+"/jobs/"+job.ID, "token-b", nil, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("other tenant: status = %d, want 404", resp.StatusCode)
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != JobSucceeded {
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %s, want succeeded", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		json.NewDecoder(do(t, http.MethodGet, gateway.URL+"/jobs/"+job.ID, "token-a", nil, "").Body).Decode(&job)
+	}
+
+	resp = do(t, http.MethodGet, gateway.URL+"/jobs/"+job.ID+"/result", "token-a", nil, "")
+	var result landingai.ParseResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK || result.Markdown != "# Async" {
+		t.Errorf("result = %d %q", resp.StatusCode, result.Markdown)
+	}
+}
+
+func TestServer_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"markdown":""}`))
+	}, Config{Workers: 1, QueueSize: 1})
+	// Registered last so it runs before the servers shut down
+	t.Cleanup(func() { close(release) })
+
+	statuses := make(map[int]int)
+	for i := 0; i < 3; i++ {
+		body, ct := multipartBody(t, nil, "doc.pdf")
+		statuses[do(t, http.MethodPost, gateway.URL+"/jobs", "token-a", body, ct).StatusCode]++
+		// Let the worker pick up the first job so the queue slot frees up once
+		time.Sleep(50 * time.Millisecond)
+	}
+	if statuses[http.StatusAccepted] != 2 || statuses[http.StatusServiceUnavailable] != 1 {
+		t.Errorf("statuses = %v, want 2 accepted and 1 rejected", statuses)
+	}
+}
+
+func TestServer_UsageByTag(t *testing.T) {
+	gateway := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markdown":"ok","metadata":{"page_count":1,"credit_usage":3}}`))
+	}, Config{})
+
+	for _, customer := range []string{"acme", "acme", "globex"} {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		writer.WriteField("document_url", "https://example.com/a.pdf")
+		writer.WriteField("tag", "customer="+customer)
+		writer.Close()
+		if resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", &buf, writer.FormDataContentType()); resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+	}
+
+	var usage Usage
+	json.NewDecoder(do(t, http.MethodGet, gateway.URL+"/usage", "token-a", nil, "").Body).Decode(&usage)
+	if usage.Requests != 3 || usage.CreditUsage != 9 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if acme := usage.ByTag["customer=acme"]; acme.Requests != 2 || acme.CreditUsage != 6 {
+		t.Errorf("customer=acme usage = %+v", acme)
+	}
+	if globex := usage.ByTag["customer=globex"]; globex.Requests != 1 {
+		t.Errorf("customer=globex usage = %+v", globex)
+	}
+
+	body, ct := multipartBody(t, map[string]string{"tag": "no-equals"}, "doc.pdf")
+	if resp := do(t, http.MethodPost, gateway.URL+"/parse", "token-a", body, ct); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid tag: status = %d, want 400", resp.StatusCode)
+	}
+}
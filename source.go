@@ -0,0 +1,236 @@
+package landingai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DocumentSource supplies the content of a document to upload.
+// Open is called once per attempt, so sources that can be reopened support
+// retries; the returned reader is streamed into the upload and then closed.
+type DocumentSource interface {
+	Open(ctx context.Context) (rc io.ReadCloser, name string, err error)
+}
+
+// ErrSourceConsumed is returned when a single-use source is opened twice
+var ErrSourceConsumed = errors.New("landingai: document source can only be read once")
+
+// FileSource reads a document from the local filesystem
+type FileSource string
+
+// Open implements DocumentSource
+func (f FileSource) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	file, err := os.Open(string(f))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return file, filepath.Base(string(f)), nil
+}
+
+// BytesSource is an in-memory document
+type BytesSource struct {
+	Data []byte
+	Name string
+}
+
+// Open implements DocumentSource
+func (s BytesSource) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	return io.NopCloser(bytes.NewReader(s.Data)), s.Name, nil
+}
+
+// readerSource streams a document from an io.Reader exactly once
+type readerSource struct {
+	mu   sync.Mutex
+	r    io.Reader
+	name string
+	used bool
+}
+
+// ReaderSource streams a document from r. The reader can only be consumed once,
+// so requests using it are not retried and cannot derive idempotency keys.
+func ReaderSource(r io.Reader, name string) DocumentSource {
+	return &readerSource{r: r, name: name}
+}
+
+// Open implements DocumentSource
+func (s *readerSource) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used {
+		return nil, "", ErrSourceConsumed
+	}
+	s.used = true
+	if rc, ok := s.r.(io.ReadCloser); ok {
+		return rc, s.name, nil
+	}
+	return io.NopCloser(s.r), s.name, nil
+}
+
+// HTTPSource downloads a document over HTTP(S), e.g. from an endpoint that
+// needs authentication headers, and streams it into the upload
+type HTTPSource struct {
+	URL    string
+	Header http.Header
+	// Client is used for the download. When nil, requests use the parse
+	// client's HTTP client, and Open called directly uses a client with
+	// DefaultTimeout.
+	Client *http.Client
+}
+
+// defaultDownloadClient bounds downloads of HTTPSource values opened outside a request
+var defaultDownloadClient = &http.Client{Timeout: DefaultTimeout}
+
+// Open implements DocumentSource
+func (s HTTPSource) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create download request: %w", err)
+	}
+	for k, vs := range s.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = defaultDownloadClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download document: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("failed to download document: status %d", resp.StatusCode)
+	}
+	return resp.Body, downloadName(resp), nil
+}
+
+// Identity implements IdentifiableSource. Headers are not part of the identity.
+func (s HTTPSource) Identity() string {
+	return s.URL
+}
+
+// downloadName returns the file name from Content-Disposition or the URL path
+func downloadName(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	return uriName(resp.Request.URL)
+}
+
+func uriName(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "document"
+	}
+	return name
+}
+
+// Fetcher fetches documents for a URI scheme, such as "s3" or "gs"
+type Fetcher interface {
+	Fetch(ctx context.Context, uri *url.URL) (rc io.ReadCloser, name string, err error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface
+type FetcherFunc func(ctx context.Context, uri *url.URL) (io.ReadCloser, string, error)
+
+// Fetch implements Fetcher
+func (f FetcherFunc) Fetch(ctx context.Context, uri *url.URL) (io.ReadCloser, string, error) {
+	return f(ctx, uri)
+}
+
+// MapFetcher is an in-memory Fetcher keyed by full URI, for tests
+type MapFetcher map[string][]byte
+
+// Fetch implements Fetcher
+func (m MapFetcher) Fetch(ctx context.Context, uri *url.URL) (io.ReadCloser, string, error) {
+	data, ok := m[uri.String()]
+	if !ok {
+		return nil, "", fmt.Errorf("%s: %w", uri, os.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), uriName(uri), nil
+}
+
+// WithFetcher registers a fetcher for a URI scheme used by WithSourceURI,
+// e.g. WithFetcher("s3", s3Fetcher). Built-in handling of "file", "http" and
+// "https" can be overridden the same way.
+func WithFetcher(scheme string, f Fetcher) ClientOption {
+	return func(c *Client) {
+		if c.fetchers == nil {
+			c.fetchers = make(map[string]Fetcher)
+		}
+		c.fetchers[strings.ToLower(scheme)] = f
+	}
+}
+
+// uriSource resolves a URI through the client's fetchers when opened
+type uriSource struct {
+	client *Client
+	uri    string
+}
+
+// Open implements DocumentSource
+func (s uriSource) Open(ctx context.Context) (io.ReadCloser, string, error) {
+	u, err := url.Parse(s.uri)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid source URI: %w", err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if f, ok := s.client.fetchers[scheme]; ok {
+		return f.Fetch(ctx, u)
+	}
+
+	switch scheme {
+	case "", "file":
+		return FileSource(u.Path).Open(ctx)
+	case "http", "https":
+		return HTTPSource{URL: s.uri, Client: s.client.httpClient}.Open(ctx)
+	default:
+		return nil, "", fmt.Errorf("no fetcher registered for scheme %q", u.Scheme)
+	}
+}
+
+// Identity implements IdentifiableSource
+func (s uriSource) Identity() string {
+	return s.uri
+}
+
+// sourceReadError marks a failure to read the document while it is uploaded,
+// so it is not mistaken for a failure of the API
+type sourceReadError struct {
+	err error
+}
+
+func (e *sourceReadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sourceReadError) Unwrap() error {
+	return e.err
+}
+
+// sourceReader wraps read errors of a document in sourceReadError
+type sourceReader struct {
+	r io.Reader
+}
+
+func (s sourceReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &sourceReadError{err: err}
+	}
+	return n, err
+}
@@ -0,0 +1,133 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// uploadServer records the name and content of the uploaded document
+func uploadServer(t *testing.T, name, content *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			w.WriteHeader(StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		*name, *content = header.Filename, string(data)
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+}
+
+func TestParseRequestBuilder_WithSourceURI(t *testing.T) {
+	var name, content string
+	server := uploadServer(t, &name, &content)
+	defer server.Close()
+
+	client := NewClient("test-api-key",
+		WithBaseURL(server.URL),
+		WithFetcher("s3", MapFetcher{"s3://bucket/invoices/inv-1.pdf": []byte("%PDF private")}),
+	)
+
+	_, err := client.Parse(context.Background()).WithSourceURI("s3://bucket/invoices/inv-1.pdf").Do()
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if name != "inv-1.pdf" || content != "%PDF private" {
+		t.Errorf("uploaded %q = %q", name, content)
+	}
+
+	_, err = client.Parse(context.Background()).WithSourceURI("gs://bucket/doc.pdf").Do()
+	if err == nil || !strings.Contains(err.Error(), `no fetcher registered for scheme "gs"`) {
+		t.Errorf("Do() error = %v, want missing fetcher", err)
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="scan.png"`)
+		w.Write([]byte("png bytes"))
+	}))
+	defer origin.Close()
+
+	var name, content string
+	server := uploadServer(t, &name, &content)
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	src := HTTPSource{URL: origin.URL + "/download", Header: http.Header{"X-Token": {"secret"}}}
+	if _, err := client.Parse(context.Background()).WithSource(src).Do(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if name != "scan.png" || content != "png bytes" {
+		t.Errorf("uploaded %q = %q", name, content)
+	}
+}
+
+func TestHTTPSource_UsesClientHTTPClient(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pdf bytes"))
+	}))
+	defer origin.Close()
+
+	var name, content string
+	server := uploadServer(t, &name, &content)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var paths []string
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithHTTPClient(httpClient))
+
+	if _, err := client.Parse(context.Background()).WithSource(HTTPSource{URL: origin.URL + "/a.pdf"}).Do(); err != nil {
+		t.Fatalf("WithSource() Do() error = %v", err)
+	}
+	if _, err := client.Parse(context.Background()).WithSourceURI(origin.URL + "/b.pdf").Do(); err != nil {
+		t.Fatalf("WithSourceURI() Do() error = %v", err)
+	}
+	// Downloads stream into the upload, so the order of the two is not fixed
+	sort.Strings(paths)
+	want := "/a.pdf,/b.pdf,/v1/ade/parse,/v1/ade/parse"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("requests through the client's HTTP client = %s, want %s", got, want)
+	}
+	if defaultDownloadClient.Timeout == 0 {
+		t.Error("default download client has no timeout")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestReaderSource(t *testing.T) {
+	src := ReaderSource(strings.NewReader("stream"), "doc.txt")
+	rc, name, err := src.Open(context.Background())
+	if err != nil || name != "doc.txt" {
+		t.Fatalf("Open() = %q, %v", name, err)
+	}
+	rc.Close()
+	if _, _, err := src.Open(context.Background()); !errors.Is(err, ErrSourceConsumed) {
+		t.Errorf("second Open() error = %v, want ErrSourceConsumed", err)
+	}
+}
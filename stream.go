@@ -0,0 +1,185 @@
+package landingai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ChunkEventType identifies the part of a response carried by a ChunkEvent
+type ChunkEventType string
+
+const (
+	ChunkEventMarkdown  ChunkEventType = "markdown"
+	ChunkEventChunk     ChunkEventType = "chunk"
+	ChunkEventSplit     ChunkEventType = "split"
+	ChunkEventGrounding ChunkEventType = "grounding"
+	ChunkEventMetadata  ChunkEventType = "metadata"
+)
+
+// ChunkEvent is one element of a streamed parse response.
+// Only the field matching Type is set.
+type ChunkEvent struct {
+	Type ChunkEventType
+
+	Markdown string
+	Chunk    *ParseChunk
+	Split    *ParseSplit
+	// GroundingID is the chunk or table cell ID of a grounding entry
+	GroundingID string
+	Grounding   *ParseResponseGrounding
+	Metadata    *ParseMetadata
+}
+
+// DoStream executes the parse request and decodes the response incrementally,
+// calling fn for each chunk, split and grounding entry as it is read, and once
+// each for the markdown and metadata. Events follow the order of the response
+// body, so callers must not assume metadata comes first.
+//
+// Unlike Do, the response is never held in memory as a whole, which keeps
+// memory flat for very large documents. If fn returns an error, the response
+// is abandoned and DoStream returns that error unwrapped. Streamed requests
+// are not deduplicated by idempotency key, since a stream cannot be shared.
+func (b *ParseRequestBuilder) DoStream(fn func(ChunkEvent) error) error {
+	key, err := b.prepare()
+	if err != nil {
+		return err
+	}
+	return b.execute(key, func(_ *ResponseInfo, body io.Reader) error {
+		return decodeStream(body, fn)
+	})
+}
+
+// callbackError marks errors returned by the DoStream callback so they are
+// passed through without decoding context
+type callbackError struct{ err error }
+
+func (e callbackError) Error() string { return e.err.Error() }
+
+// decodeStream walks the top-level response object with a token decoder
+func decodeStream(r io.Reader, fn func(ChunkEvent) error) error {
+	err := walkResponse(json.NewDecoder(r), func(ev ChunkEvent) error {
+		if err := fn(ev); err != nil {
+			return callbackError{err}
+		}
+		return nil
+	})
+	if cbErr, ok := err.(callbackError); ok {
+		return cbErr.err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func walkResponse(dec *json.Decoder, fn func(ChunkEvent) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "markdown":
+			var markdown string
+			if err := dec.Decode(&markdown); err != nil {
+				return err
+			}
+			err = fn(ChunkEvent{Type: ChunkEventMarkdown, Markdown: markdown})
+		case "chunks":
+			err = walkArray(dec, func() error {
+				var chunk ParseChunk
+				if err := dec.Decode(&chunk); err != nil {
+					return err
+				}
+				return fn(ChunkEvent{Type: ChunkEventChunk, Chunk: &chunk})
+			})
+		case "splits":
+			err = walkArray(dec, func() error {
+				var split ParseSplit
+				if err := dec.Decode(&split); err != nil {
+					return err
+				}
+				return fn(ChunkEvent{Type: ChunkEventSplit, Split: &split})
+			})
+		case "grounding":
+			err = walkObject(dec, func(id string) error {
+				var grounding ParseResponseGrounding
+				if err := dec.Decode(&grounding); err != nil {
+					return err
+				}
+				return fn(ChunkEvent{Type: ChunkEventGrounding, GroundingID: id, Grounding: &grounding})
+			})
+		case "metadata":
+			var metadata ParseMetadata
+			if err := dec.Decode(&metadata); err != nil {
+				return err
+			}
+			err = fn(ChunkEvent{Type: ChunkEventMetadata, Metadata: &metadata})
+		default:
+			// Skip fields the SDK does not know about
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// walkArray calls each for every element of a JSON array, which may be null
+func walkArray(dec *json.Decoder, each func() error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array, got %v", tok)
+	}
+	for dec.More() {
+		if err := each(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// walkObject calls each with every key of a JSON object, which may be null;
+// each must decode the value
+func walkObject(dec *json.Decoder, each func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if err := each(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package landingai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+const streamResponse = `{
+	"markdown": "# Report\n\nTotal: 42",
+	"chunks": [
+		{"markdown": "# Report", "type": "text", "id": "c1", "grounding": {"page": 0}},
+		{"markdown": "Total: 42", "type": "text", "id": "c2", "grounding": {"page": 1}}
+	],
+	"splits": [{"class": "page", "identifier": "page-0", "pages": [0], "chunks": ["c1"]}],
+	"grounding": {
+		"c1": {"box": {"left": 0.1, "top": 0.1, "right": 0.9, "bottom": 0.2}, "page": 0, "type": "chunkText"},
+		"c2": {"box": {"left": 0.1, "top": 0.3, "right": 0.9, "bottom": 0.4}, "page": 1, "type": "chunkText"}
+	},
+	"future_field": {"nested": [1, 2, {"deep": true}]},
+	"metadata": {"filename": "report.pdf", "page_count": 2, "credit_usage": 6}
+}`
+
+func streamServer(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("test-api-key", WithBaseURL(server.URL))
+}
+
+func TestParseRequestBuilder_DoStream(t *testing.T) {
+	client := streamServer(t, http.StatusOK, streamResponse)
+
+	var types []ChunkEventType
+	var chunkIDs, groundingIDs []string
+	var metadata *ParseMetadata
+	err := client.Parse(context.Background()).
+		WithURL("https://example.com/report.pdf").
+		DoStream(func(ev ChunkEvent) error {
+			types = append(types, ev.Type)
+			switch ev.Type {
+			case ChunkEventChunk:
+				chunkIDs = append(chunkIDs, ev.Chunk.ID)
+			case ChunkEventGrounding:
+				groundingIDs = append(groundingIDs, ev.GroundingID)
+				if ev.Grounding.Type != GroundingTypeChunkText {
+					t.Errorf("grounding %s type = %q", ev.GroundingID, ev.Grounding.Type)
+				}
+			case ChunkEventSplit:
+				if ev.Split.Identifier != "page-0" {
+					t.Errorf("split = %+v", ev.Split)
+				}
+			case ChunkEventMarkdown:
+				if ev.Markdown != "# Report\n\nTotal: 42" {
+					t.Errorf("markdown = %q", ev.Markdown)
+				}
+			case ChunkEventMetadata:
+				metadata = ev.Metadata
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("DoStream() error = %v", err)
+	}
+
+	want := []ChunkEventType{
+		ChunkEventMarkdown, ChunkEventChunk, ChunkEventChunk, ChunkEventSplit,
+		ChunkEventGrounding, ChunkEventGrounding, ChunkEventMetadata,
+	}
+	if !slices.Equal(types, want) {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+	if !slices.Equal(chunkIDs, []string{"c1", "c2"}) || !slices.Equal(groundingIDs, []string{"c1", "c2"}) {
+		t.Errorf("chunks = %v, grounding = %v", chunkIDs, groundingIDs)
+	}
+	if metadata == nil || metadata.PageCount != 2 || metadata.CreditUsage != 6 {
+		t.Errorf("metadata = %+v", metadata)
+	}
+}
+
+func TestParseRequestBuilder_DoStreamCallbackError(t *testing.T) {
+	client := streamServer(t, http.StatusOK, streamResponse)
+
+	stop := errors.New("stop")
+	var chunks int
+	err := client.Parse(context.Background()).
+		WithURL("https://example.com/report.pdf").
+		DoStream(func(ev ChunkEvent) error {
+			if ev.Type == ChunkEventChunk {
+				chunks++
+				return stop
+			}
+			return nil
+		})
+	if err != stop {
+		t.Errorf("DoStream() error = %v, want the callback error", err)
+	}
+	if chunks != 1 {
+		t.Errorf("callback saw %d chunks after returning an error, want 1", chunks)
+	}
+}
+
+func TestParseRequestBuilder_DoStreamNulls(t *testing.T) {
+	client := streamServer(t, http.StatusOK, `{"markdown":"","chunks":null,"splits":null,"grounding":null,"metadata":{"page_count":0}}`)
+
+	var types []ChunkEventType
+	err := client.Parse(context.Background()).
+		WithURL("https://example.com/empty.pdf").
+		DoStream(func(ev ChunkEvent) error {
+			types = append(types, ev.Type)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("DoStream() error = %v", err)
+	}
+	if !slices.Equal(types, []ChunkEventType{ChunkEventMarkdown, ChunkEventMetadata}) {
+		t.Errorf("events = %v", types)
+	}
+}
+
+func TestParseRequestBuilder_DoStreamErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{
+			name:   "api error",
+			status: http.StatusTooManyRequests,
+			body:   `{"detail":"slow down"}`,
+			check: func(err error) bool {
+				var apiErr *APIError
+				return errors.As(err, &apiErr) && apiErr.IsRateLimited()
+			},
+		},
+		{
+			name:   "truncated body",
+			status: http.StatusOK,
+			body:   `{"chunks":[{"id":"c1"},`,
+			check:  func(err error) bool { return err != nil },
+		},
+		{
+			name:   "not an object",
+			status: http.StatusOK,
+			body:   `[]`,
+			check:  func(err error) bool { return err != nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := streamServer(t, tt.status, tt.body)
+			err := client.Parse(context.Background()).
+				WithURL("https://example.com/doc.pdf").
+				DoStream(func(ChunkEvent) error { return nil })
+			if !tt.check(err) {
+				t.Errorf("DoStream() error = %v", err)
+			}
+		})
+	}
+}
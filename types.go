@@ -1,5 +1,7 @@
 package landingai
 
+import "encoding/json"
+
 // ChunkType represents the type of content chunk extracted from a document
 type ChunkType string
 
@@ -51,6 +53,64 @@ type ParseGroundingBox struct {
 	Bottom float64 `json:"bottom"`
 }
 
+// Width returns the width of the box
+func (b ParseGroundingBox) Width() float64 {
+	return b.Right - b.Left
+}
+
+// Height returns the height of the box
+func (b ParseGroundingBox) Height() float64 {
+	return b.Bottom - b.Top
+}
+
+// Area returns the area of the box, or 0 for an empty box
+func (b ParseGroundingBox) Area() float64 {
+	if b.Width() <= 0 || b.Height() <= 0 {
+		return 0
+	}
+	return b.Width() * b.Height()
+}
+
+// Union returns the smallest box containing both boxes
+func (b ParseGroundingBox) Union(other ParseGroundingBox) ParseGroundingBox {
+	if b.Area() == 0 {
+		return other
+	}
+	if other.Area() == 0 {
+		return b
+	}
+	return ParseGroundingBox{
+		Left:   min(b.Left, other.Left),
+		Top:    min(b.Top, other.Top),
+		Right:  max(b.Right, other.Right),
+		Bottom: max(b.Bottom, other.Bottom),
+	}
+}
+
+// Intersect returns the overlapping region of both boxes, or an empty box if they do not overlap
+func (b ParseGroundingBox) Intersect(other ParseGroundingBox) ParseGroundingBox {
+	box := ParseGroundingBox{
+		Left:   max(b.Left, other.Left),
+		Top:    max(b.Top, other.Top),
+		Right:  min(b.Right, other.Right),
+		Bottom: min(b.Bottom, other.Bottom),
+	}
+	if box.Area() == 0 {
+		return ParseGroundingBox{}
+	}
+	return box
+}
+
+// IoU returns the intersection over union of both boxes, from 0 (disjoint) to 1 (identical)
+func (b ParseGroundingBox) IoU(other ParseGroundingBox) float64 {
+	inter := b.Intersect(other).Area()
+	union := b.Area() + other.Area() - inter
+	if union == 0 {
+		return 0
+	}
+	return inter / union
+}
+
 // ParseGrounding represents the location of a chunk within the original document
 type ParseGrounding struct {
 	Box  ParseGroundingBox `json:"box"`
@@ -91,6 +151,9 @@ type ParseMetadata struct {
 	JobID       string  `json:"job_id"`
 	Version     *string `json:"version"`
 	FailedPages []int   `json:"failed_pages,omitempty"`
+
+	// Extra holds fields the SDK does not model yet, so they survive a round trip
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // ParseResponse represents the complete response from the Parse API
@@ -100,12 +163,16 @@ type ParseResponse struct {
 	Splits    []ParseSplit                      `json:"splits"`
 	Grounding map[string]ParseResponseGrounding `json:"grounding"`
 	Metadata  ParseMetadata                     `json:"metadata"`
+
+	// Extra holds fields the SDK does not model yet, so they survive a round trip
+	Extra map[string]json.RawMessage `json:"-"`
+
+	raw  []byte
+	http *ResponseInfo
 }
 
-// ParseRequest represents a request to parse a document
-type ParseRequest struct {
-	Model       *string    `json:"model,omitempty"`
-	Document    []byte     `json:"-"` // Handled as multipart file upload
-	DocumentURL *string    `json:"document_url,omitempty"`
-	Split       *SplitType `json:"split,omitempty"`
+// GroundingTypeOf returns the grounding type recorded for the given chunk ID,
+// or an empty string if the response has no grounding entry for it
+func (r *ParseResponse) GroundingTypeOf(chunkID string) GroundingType {
+	return r.Grounding[chunkID].Type
 }
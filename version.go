@@ -0,0 +1,128 @@
+package landingai
+
+import "strings"
+
+// Version is the version of this SDK. It is bumped together with CHANGELOG.md
+// when a release is tagged, and is sent in the default User-Agent and recorded
+// in saved results.
+const Version = "0.1.0"
+
+// APIVersion is the version of the Landing AI API this SDK calls
+const APIVersion = "v1"
+
+// Feature is an SDK feature that depends on the model serving a request
+type Feature string
+
+const (
+	FeatureMarkdown           Feature = "markdown"
+	FeatureChunks             Feature = "chunks"
+	FeatureGrounding          Feature = "grounding"
+	FeatureSplit              Feature = "split"
+	FeatureTableCellGrounding Feature = "table_cell_grounding"
+	// FeatureExtendedChunks covers logo, card, attestation and scan code chunks
+	FeatureExtendedChunks Feature = "extended_chunks"
+)
+
+// Unreleased is the Since value of models added after the latest release.
+// Replace it with the new Version when tagging a release.
+const Unreleased = "unreleased"
+
+// ModelSupport records which SDK release added support for a model snapshot
+// and which features the SDK handles for it
+type ModelSupport struct {
+	Model Model
+	// API is the API version serving the model
+	API string
+	// Since is the first SDK version that knows the model, or Unreleased
+	Since string
+	// Features are derived from the capabilities of the model's family
+	Features []Feature
+}
+
+// compatibility lists every model snapshot known to this SDK version.
+// Add new snapshots here when adding their Model constants; features come
+// from modelCapabilities.
+var compatibility = []ModelSupport{
+	{Model: ModelDPT220250919, API: APIVersion, Since: Unreleased},
+}
+
+// featuresOf lists the SDK features backed by a model family's capabilities
+func featuresOf(caps ModelCapabilities) []Feature {
+	features := []Feature{FeatureMarkdown, FeatureChunks, FeatureGrounding}
+	if caps.Split {
+		features = append(features, FeatureSplit)
+	}
+	if caps.SupportsGroundingType(GroundingTypeTableCell) {
+		features = append(features, FeatureTableCellGrounding)
+	}
+	if caps.SupportsChunkType(ChunkTypeLogo) {
+		features = append(features, FeatureExtendedChunks)
+	}
+	return features
+}
+
+// withFeatures returns a copy of s with its features filled in
+func (s ModelSupport) withFeatures() ModelSupport {
+	caps, _ := s.Model.Capabilities()
+	s.Features = featuresOf(caps)
+	return s
+}
+
+// Compatibility returns the model snapshots known to this SDK version
+func Compatibility() []ModelSupport {
+	table := make([]ModelSupport, len(compatibility))
+	for i, s := range compatibility {
+		table[i] = s.withFeatures()
+	}
+	return table
+}
+
+// SupportFor returns the compatibility entry of a model snapshot
+func SupportFor(model Model) (ModelSupport, bool) {
+	for _, s := range compatibility {
+		if s.Model == model {
+			return s.withFeatures(), true
+		}
+	}
+	return ModelSupport{}, false
+}
+
+// listed reports whether model is in the compatibility table. Model names
+// are matched case-insensitively, as the API reports them in varying case.
+func listed(model Model) bool {
+	for _, s := range compatibility {
+		if strings.EqualFold(string(s.Model), string(model)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCompatibility reports whether this SDK knows the model version that
+// produced resp. If the server used an unknown model, or a dated snapshot not
+// listed in Compatibility, it logs a warning, since the response may contain
+// fields or chunk types the SDK does not model yet; they are kept in Extra.
+// Latest aliases of known families and responses without a version are
+// considered compatible.
+func (c *Client) CheckCompatibility(resp *ParseResponse) bool {
+	if resp == nil || resp.Metadata.Version == nil || *resp.Metadata.Version == "" {
+		return true
+	}
+	version := *resp.Metadata.Version
+
+	info, err := ParseModel(version)
+	if err != nil {
+		c.logger.Warn("landingai: server used a model unknown to this SDK, consider upgrading",
+			"version", version, "sdk_version", Version)
+		return false
+	}
+	if info.Latest {
+		return true
+	}
+	if !listed(info.Model) {
+		c.logger.Warn("landingai: server used a model snapshot unknown to this SDK, consider upgrading",
+			"version", version, "sdk_version", Version)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package landingai
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVersion_MatchesChangelog(t *testing.T) {
+	data, err := os.ReadFile("CHANGELOG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`(?m)^## \[(\d+\.\d+\.\d+)\]`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("no release found in CHANGELOG.md")
+	}
+	if got := string(m[1]); got != Version {
+		t.Errorf("latest CHANGELOG release = %s, Version = %s", got, Version)
+	}
+	if !strings.Contains(DefaultUserAgent(), "landingai-go/"+Version) {
+		t.Errorf("DefaultUserAgent() = %q", DefaultUserAgent())
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	support, ok := SupportFor(ModelDPT220250919)
+	if !ok || support.API != APIVersion || support.Since != Unreleased {
+		t.Errorf("SupportFor(%s) = %+v, %v", ModelDPT220250919, support, ok)
+	}
+	if _, ok := SupportFor(ModelDPT2Latest); ok {
+		t.Error("SupportFor() listed an alias")
+	}
+
+	// Features follow the family's capabilities
+	tests := []struct {
+		caps ModelCapabilities
+		want []Feature
+	}{
+		{modelCapabilities[ModelFamilyDPT2], []Feature{FeatureMarkdown, FeatureChunks, FeatureGrounding, FeatureSplit, FeatureTableCellGrounding, FeatureExtendedChunks}},
+		{modelCapabilities[ModelFamilyDPT2Mini], []Feature{FeatureMarkdown, FeatureChunks, FeatureGrounding, FeatureSplit}},
+		{ModelCapabilities{ChunkTypes: baseChunkTypes}, []Feature{FeatureMarkdown, FeatureChunks, FeatureGrounding}},
+	}
+	for _, tt := range tests {
+		if got := featuresOf(tt.caps); !slices.Equal(got, tt.want) {
+			t.Errorf("featuresOf() = %v, want %v", got, tt.want)
+		}
+	}
+
+	support.Features[0] = "changed"
+	if again, _ := SupportFor(ModelDPT220250919); again.Features[0] == "changed" {
+		t.Error("SupportFor shares the table's feature slice")
+	}
+}
+
+func TestClient_CheckCompatibility(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "", want: true},
+		{version: "dpt-2-20250919", want: true},
+		{version: "dpt-2-latest", want: true},
+		{version: "dpt-2-20990101", want: false},
+		{version: "DPT-2-20250919", want: true},
+		{version: "dpt-2-20240101", want: false},
+		{version: "DPT-2-mini-20990101", want: false},
+		{version: "dpt-1-20250101", want: false},
+		{version: "dpt-1-latest", want: true},
+		{version: "dpt-9-latest", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			var logs bytes.Buffer
+			client := NewClient("test-api-key", WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+			resp := &ParseResponse{}
+			if tt.version != "" {
+				resp.Metadata.Version = &tt.version
+			}
+			if got := client.CheckCompatibility(resp); got != tt.want {
+				t.Errorf("CheckCompatibility() = %v, want %v", got, tt.want)
+			}
+			if warned := strings.Contains(logs.String(), "level=WARN"); warned == tt.want {
+				t.Errorf("warned = %v, logs = %q", warned, logs.String())
+			}
+		})
+	}
+}
+
+func TestClient_CheckCompatibility_NilLogger(t *testing.T) {
+	version := "dpt-9-latest"
+	client := NewClient("test-api-key", WithLogger(nil))
+	if client.CheckCompatibility(&ParseResponse{Metadata: ParseMetadata{Version: &version}}) {
+		t.Error("CheckCompatibility() = true for an unknown model")
+	}
+}
@@ -0,0 +1,108 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// stateVersion is the version of the state file format
+const stateVersion = 1
+
+// FileState records the processing of one file
+type FileState struct {
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mod_time"`
+	ProcessedAt time.Time `json:"processed_at"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// state is the persisted set of processed files, keyed by file name
+type state struct {
+	mu    sync.Mutex
+	path  string
+	dirty bool
+	Files map[string]*FileState
+}
+
+type stateFile struct {
+	Version int                   `json:"version"`
+	Files   map[string]*FileState `json:"files"`
+}
+
+func loadState(path string) (*state, error) {
+	st := &state{path: path, Files: make(map[string]*FileState)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var f stateFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode state file %s: %w", path, err)
+	}
+	if f.Version > stateVersion {
+		return nil, fmt.Errorf("state file %s has unsupported version %d", path, f.Version)
+	}
+	if f.Files != nil {
+		st.Files = f.Files
+	}
+	return st, nil
+}
+
+// done reports whether the file, in its current version, needs no further attempts
+func (s *state) done(name string, info fs.FileInfo, maxAttempts int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fsState, ok := s.Files[name]
+	if !ok || fsState.Size != info.Size() || !fsState.ModTime.Equal(info.ModTime()) {
+		return false
+	}
+	return fsState.Error == "" || fsState.Attempts >= maxAttempts
+}
+
+// record stores the outcome of a parse attempt
+func (s *state) record(name string, size int64, modTime time.Time, err error, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fsState, ok := s.Files[name]
+	if !ok || fsState.Size != size || !fsState.ModTime.Equal(modTime) {
+		fsState = &FileState{Size: size, ModTime: modTime}
+		s.Files[name] = fsState
+	}
+	fsState.Attempts++
+	fsState.ProcessedAt = now.UTC()
+	fsState.Error = ""
+	if err != nil {
+		fsState.Error = err.Error()
+	}
+	s.dirty = true
+}
+
+// save writes the state file if anything changed
+func (s *state) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(stateFile{Version: stateVersion, Files: s.Files}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state file: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,348 @@
+// Package watch parses documents dropped into a directory.
+//
+// A Watcher polls the directory, waits until new files stop changing, parses
+// them and writes the results next to them or to an output directory. Processed
+// files are tracked in a state file so restarts do not parse them again.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+const (
+	// DefaultInterval is the default polling interval
+	DefaultInterval = 2 * time.Second
+	// DefaultDebounce is how long a file must stay unchanged before it is parsed
+	DefaultDebounce = 2 * time.Second
+	// DefaultMaxAttempts is the default number of attempts for a failing file
+	DefaultMaxAttempts = 3
+	// DefaultStateFile is the state file name used when Options.StateFile is empty
+	DefaultStateFile = ".landingai-watch.json"
+)
+
+// DefaultPatterns are the file patterns watched when Options.Patterns is empty
+var DefaultPatterns = []string{
+	"*.pdf", "*.png", "*.jpg", "*.jpeg", "*.webp", "*.bmp", "*.tif", "*.tiff",
+	"*.xlsx", "*.xls", "*.csv", "*.tsv",
+}
+
+// Format is an output format written for each parsed document
+type Format string
+
+const (
+	// FormatJSON writes <file>.json, e.g. invoice.pdf.json, as a landingai.SaveResult archive
+	FormatJSON Format = "json"
+	// FormatMarkdown writes <file>.md, e.g. invoice.pdf.md, with the document markdown
+	FormatMarkdown Format = "md"
+)
+
+// Options configures a Watcher
+type Options struct {
+	// Dir is the directory to watch (not recursive)
+	Dir string
+	// OutputDir receives the results (default Dir)
+	OutputDir string
+	// Patterns are the file name patterns to parse (default DefaultPatterns)
+	Patterns []string
+	// Formats are the outputs to write (default JSON and Markdown)
+	Formats []Format
+
+	// Model and Split are applied to every parse request
+	Model landingai.Model
+	Split *landingai.SplitType
+
+	// Interval is the polling interval (default DefaultInterval)
+	Interval time.Duration
+	// Debounce is how long size and modification time must stay unchanged
+	// before a file is considered complete (default DefaultDebounce)
+	Debounce time.Duration
+	// MaxAttempts limits retries of a failing file until it changes (default DefaultMaxAttempts)
+	MaxAttempts int
+	// Concurrency is the number of files parsed in parallel (default 1)
+	Concurrency int
+	// StateFile tracks processed files (default OutputDir/DefaultStateFile)
+	StateFile string
+
+	// Logger receives progress and errors (default slog.Default())
+	Logger *slog.Logger
+	// OnResult is called after each parse attempt
+	OnResult func(path string, resp *landingai.ParseResponse, err error)
+}
+
+// Watcher parses new documents in a directory
+type Watcher struct {
+	client  *landingai.Client
+	opts    Options
+	state   *state
+	pending map[string]*pendingFile
+	now     func() time.Time
+}
+
+// pendingFile is a file waiting for writes to settle
+type pendingFile struct {
+	size    int64
+	modTime time.Time
+	stable  time.Time // when the current size and modification time were first seen
+}
+
+// New creates a Watcher, loading the state file if it exists
+func New(client *landingai.Client, opts Options) (*Watcher, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("watch directory is required")
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = opts.Dir
+	}
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = DefaultPatterns
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []Format{FormatJSON, FormatMarkdown}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.StateFile == "" {
+		opts.StateFile = filepath.Join(opts.OutputDir, DefaultStateFile)
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	for _, p := range opts.Patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	// Unknown formats would only fail after a billed parse
+	for _, format := range opts.Formats {
+		if format != FormatJSON && format != FormatMarkdown {
+			return nil, fmt.Errorf("unknown output format %q", format)
+		}
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	st, err := loadState(opts.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		client:  client,
+		opts:    opts,
+		state:   st,
+		pending: make(map[string]*pendingFile),
+		now:     time.Now,
+	}, nil
+}
+
+// Run polls the directory until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	w.opts.Logger.Info("landingai: watching directory", "dir", w.opts.Dir, "output", w.opts.OutputDir)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Scan(ctx); err != nil {
+			w.opts.Logger.Error("landingai: scan failed", "dir", w.opts.Dir, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan performs a single polling pass, parsing every file whose writes have settled
+func (w *Watcher) Scan(ctx context.Context) error {
+	entries, err := os.ReadDir(w.opts.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	now := w.now()
+	var ready []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !w.matches(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		name := entry.Name()
+		seen[name] = true
+
+		if w.state.done(name, info, w.opts.MaxAttempts) {
+			delete(w.pending, name)
+			continue
+		}
+
+		p, ok := w.pending[name]
+		if !ok || p.size != info.Size() || !p.modTime.Equal(info.ModTime()) {
+			w.pending[name] = &pendingFile{size: info.Size(), modTime: info.ModTime(), stable: now}
+			continue
+		}
+		if now.Sub(p.stable) >= w.opts.Debounce {
+			ready = append(ready, name)
+		}
+	}
+	for name := range w.pending {
+		if !seen[name] {
+			delete(w.pending, name)
+		}
+	}
+	sort.Strings(ready)
+
+	sem := make(chan struct{}, w.opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, name := range ready {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(name string, p *pendingFile) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			w.process(ctx, name, p)
+		}(name, w.pending[name])
+	}
+	wg.Wait()
+	return w.state.save()
+}
+
+// process parses one file and records the outcome
+func (w *Watcher) process(ctx context.Context, name string, p *pendingFile) {
+	path := filepath.Join(w.opts.Dir, name)
+	logger := w.opts.Logger.With("file", path)
+
+	builder := w.client.Parse(ctx).WithFile(path)
+	if w.opts.Model != "" {
+		builder.WithModel(w.opts.Model)
+	}
+	if w.opts.Split != nil {
+		builder.WithSplit(*w.opts.Split)
+	}
+
+	resp, err := builder.Do()
+	if err == nil {
+		err = w.writeOutputs(path, resp)
+	}
+	if ctx.Err() != nil {
+		// Shutting down; leave the file for the next run
+		return
+	}
+	if err != nil {
+		logger.Error("landingai: failed to parse document", "error", err)
+	} else {
+		logger.Info("landingai: parsed document", "pages", resp.Metadata.PageCount, "credits", resp.Metadata.CreditUsage)
+	}
+	w.state.record(name, p.size, p.modTime, err, w.now())
+	// Save right away, so a crash later in the batch does not bill this file again
+	if saveErr := w.state.save(); saveErr != nil {
+		logger.Error("landingai: failed to save state", "error", saveErr)
+	}
+
+	if w.opts.OnResult != nil {
+		w.opts.OnResult(path, resp, err)
+	}
+}
+
+// writeOutputs writes the configured result files for a parsed document.
+// Outputs are named after the document's path relative to the watched
+// directory, extension included, so x.pdf and x.png do not overwrite each other.
+func (w *Watcher) writeOutputs(path string, resp *landingai.ParseResponse) error {
+	base, err := filepath.Rel(w.opts.Dir, path)
+	if err != nil {
+		return fmt.Errorf("failed to name outputs for %s: %w", path, err)
+	}
+	for _, format := range w.opts.Formats {
+		out := filepath.Join(w.opts.OutputDir, base+"."+string(format))
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		var err error
+		switch format {
+		case FormatJSON:
+			err = writeResult(out, path, resp, w.opts)
+		case FormatMarkdown:
+			err = writeFileAtomic(out, []byte(resp.Markdown))
+		default:
+			err = fmt.Errorf("unknown output format %q", format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeResult(out, path string, resp *landingai.ParseResponse, opts Options) error {
+	source, err := landingai.SourceFromFile(path)
+	if err != nil {
+		return err
+	}
+	source.Model = opts.Model
+	source.Split = opts.Split
+
+	var sb strings.Builder
+	if err := landingai.SaveResult(&sb, resp, source); err != nil {
+		return err
+	}
+	return writeFileAtomic(out, []byte(sb.String()))
+}
+
+func (w *Watcher) matches(name string) bool {
+	lower := strings.ToLower(name)
+	for _, p := range w.opts.Patterns {
+		if ok, _ := filepath.Match(strings.ToLower(p), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so readers never see a partial result
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
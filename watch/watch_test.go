@@ -0,0 +1,190 @@
+package watch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/youssefsiam38/landingai"
+)
+
+func TestWatcher_Scan(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.FormValue("model") != "dpt-2-latest" {
+			t.Errorf("model = %q", r.FormValue("model"))
+		}
+		w.Write([]byte(`{"markdown":"# Scanned","metadata":{"page_count":1}}`))
+	}))
+	defer server.Close()
+	client := landingai.NewClient("test-api-key", landingai.WithBaseURL(server.URL))
+
+	dir, out := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "scan.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	newWatcher := func() *Watcher {
+		w, err := New(client, Options{Dir: dir, OutputDir: out, Model: landingai.ModelDPT2Latest, Debounce: time.Second})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		w.now = func() time.Time { return now }
+		return w
+	}
+
+	w := newWatcher()
+	ctx := context.Background()
+	if err := w.Scan(ctx); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("Scan() parsed a file before the debounce period")
+	}
+
+	now = now.Add(time.Second)
+	if err := w.Scan(ctx); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("server called %d times, want 1", calls.Load())
+	}
+
+	md, err := os.ReadFile(filepath.Join(out, "scan.pdf.md"))
+	if err != nil || string(md) != "# Scanned" {
+		t.Errorf("scan.pdf.md = %q, %v", md, err)
+	}
+	f, err := os.Open(filepath.Join(out, "scan.pdf.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stored, err := landingai.LoadResult(f)
+	if err != nil || stored.Source.Name != "scan.pdf" || stored.Source.SHA256 == "" {
+		t.Errorf("scan.pdf.json = %+v, %v", stored, err)
+	}
+
+	// A restarted watcher does not parse the file again
+	w = newWatcher()
+	for i := 0; i < 2; i++ {
+		now = now.Add(time.Second)
+		if err := w.Scan(ctx); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times after restart, want 1", calls.Load())
+	}
+}
+
+func TestWatcher_OutputNamesKeepExtension(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, _ := r.FormFile("document")
+		w.Write([]byte(`{"markdown":"` + header.Filename + `"}`))
+	}))
+	defer server.Close()
+	client := landingai.NewClient("test-api-key", landingai.WithBaseURL(server.URL))
+
+	dir, out := t.TempDir(), t.TempDir()
+	for _, name := range []string{"x.pdf", "x.png"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	w, err := New(client, Options{Dir: dir, OutputDir: out, Formats: []Format{FormatMarkdown}, Debounce: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if err := w.Scan(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	for _, name := range []string{"x.pdf", "x.png"} {
+		md, err := os.ReadFile(filepath.Join(out, name+".md"))
+		if err != nil || string(md) != name {
+			t.Errorf("%s.md = %q, %v", name, md, err)
+		}
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	client := landingai.NewClient("test-api-key")
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "no directory", opts: Options{}},
+		{name: "invalid pattern", opts: Options{Dir: dir, Patterns: []string{"["}}},
+		{name: "unknown format", opts: Options{Dir: dir, Formats: []Format{FormatJSON, "pdf"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(client, tt.opts); err == nil {
+				t.Error("New() succeeded")
+			}
+		})
+	}
+}
+
+func TestWatcher_SavesStateAfterEachFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	defer server.Close()
+	client := landingai.NewClient("test-api-key", landingai.WithBaseURL(server.URL))
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.pdf", "b.pdf"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each result must already be recorded on disk when the next file starts
+	var recorded []int
+	now := time.Now()
+	w, err := New(client, Options{
+		Dir:      dir,
+		Formats:  []Format{FormatMarkdown},
+		Debounce: time.Second,
+		OnResult: func(path string, resp *landingai.ParseResponse, err error) {
+			st, loadErr := loadState(filepath.Join(dir, DefaultStateFile))
+			if loadErr != nil {
+				t.Error(loadErr)
+				return
+			}
+			recorded = append(recorded, len(st.Files))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if err := w.Scan(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	if len(recorded) != 2 || recorded[0] != 1 || recorded[1] != 2 {
+		t.Errorf("files in the state file after each result = %v, want [1 2]", recorded)
+	}
+}